@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+)
+
+var (
+	addTagFlags  []string
+	addTitleFlag string
+	addWeekFlag  bool
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Save an article to the reading database in Notion",
+	Long: `Creates a page for the URL in the reading database and adds it to the
+local cache. Without --title the title is read from the page's <title>,
+falling back to the URL when the page cannot be fetched.`,
+	Example: `  readings add https://go.dev/blog/intro-generics --tag go --week`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		u, err := url.Parse(args[0])
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fmt.Fprintf(os.Stderr, "Error: not an http or https URL: %s\n", args[0])
+			os.Exit(1)
+		}
+
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		ctx := context.Background()
+		title := strings.TrimSpace(addTitleFlag)
+		if title == "" {
+			if title, err = fetchTitle(ctx, u.String()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v; using the URL as title\n", err)
+				title = u.String()
+			}
+		}
+
+		article, err := svc.Add(ctx, readings.Article{
+			Title: title,
+			URL:   u.String(),
+			Tags:  editTags(nil, addTagFlags, nil),
+		})
+		if errors.Is(err, readings.ErrDuplicate) {
+			fmt.Fprintf(os.Stderr, "Error: %v (%s)\n", err, article.ID)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added: %s\n", displayTitle(article))
+
+		if addWeekFlag {
+			if _, err := svc.ToggleReadingInCurrentWeek(ctx, article.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("Added to this week's reading list")
+			reportQueued(ctx, svc)
+		}
+	},
+}
+
+// maxTitleBytes bounds how much of a page is read looking for its title.
+const maxTitleBytes = 1 << 20
+
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// fetchTitle returns the text of the page's <title>, with entities decoded
+// and whitespace collapsed.
+func fetchTitle(ctx context.Context, pageURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch title: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch title: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch title: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTitleBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch title: %w", err)
+	}
+	match := titlePattern.FindSubmatch(body)
+	if match == nil {
+		return "", errors.New("page has no title")
+	}
+	title := strings.Join(strings.Fields(html.UnescapeString(string(match[1]))), " ")
+	if title == "" {
+		return "", errors.New("page has no title")
+	}
+	return title, nil
+}
+
+func init() {
+	addCmd.Flags().StringSliceVarP(&addTagFlags, "tag", "t", nil, "Tags to set (repeatable)")
+	addCmd.Flags().StringVar(&addTitleFlag, "title", "", "Title to use instead of the page's")
+	addCmd.Flags().BoolVarP(&addWeekFlag, "week", "w", false, "Also add the article to this week's reading list")
+	rootCmd.AddCommand(addCmd)
+}
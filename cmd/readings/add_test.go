@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/notion/notiontest"
+	"productivity.go/internal/readings"
+)
+
+func TestAddCommand(t *testing.T) {
+	srv := fakeHome(t)
+	t.Cleanup(func() { addTagFlags, addTitleFlag, addWeekFlag = nil, "", false })
+
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "<html><head><title>\n  Rust &amp; Go\n</title></head></html>")
+	}))
+	t.Cleanup(site.Close)
+
+	today := time.Now()
+	week := srv.AddPage(testWeeksDB, notiontest.Properties{
+		"Name":    notiontest.Title(today.Format("2006-01-02")),
+		"🗓️ Span": notiontest.Date(today.AddDate(0, 0, -3).Format("2006-01-02"), today.AddDate(0, 0, 3).Format("2006-01-02")),
+	})
+	runCLI(t, "add", site.URL+"/post", "--tag", "go", "--tag", "rust", "--week")
+	assert.Equal(t, []string{"Rust & Go"}, cachedTitles(t), "Cached without a sync")
+	assert.Equal(t, 1, countRequests(srv, "POST /v1/pages"))
+
+	svc, store, err := openService()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	article, err := svc.Find(ctx, site.URL+"/post")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "rust"}, article.Tags)
+	runCLI(t, "sync")
+	page, ok := srv.Page(week)
+	require.True(t, ok)
+	assert.Equal(t, []string{article.ID}, notiontest.RelationIDs(page.Properties["📑 Reading List"]))
+
+	// Notion keeps the article through the next full sync.
+	runCLI(t, "sync", "--full")
+	assert.Equal(t, []string{"Rust & Go"}, cachedTitles(t))
+
+	_, err = fetchTitle(ctx, site.URL+"/missing")
+	assert.ErrorContains(t, err, "404")
+
+	_, err = svc.Add(ctx, readings.Article{Title: "Again", URL: site.URL + "/post"})
+	assert.ErrorIs(t, err, readings.ErrDuplicate)
+}
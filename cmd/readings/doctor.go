@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/config"
+	"productivity.go/internal/doctor"
+	"productivity.go/internal/storage"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration, Notion schema and local cache",
+	Run: func(cmd *cobra.Command, args []string) {
+		env := doctor.Env{}
+		env.Config, env.ConfigErr = config.Load()
+		env.NetrcPath, _ = config.NetrcPath()
+		env.CachePath, _ = storage.DefaultPath()
+		if env.Config != nil && env.Config.NotionAPIKey != "" {
+			env.Notion = newNotionClient(env.Config)
+		}
+
+		report := doctor.Run(context.Background(), env)
+		report.Write(os.Stdout)
+
+		if !report.OK() {
+			fmt.Fprintln(os.Stderr, "\nSome checks failed.")
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var doneCmd = &cobra.Command{
+	Use:   "done <id|url>",
+	Short: "Mark an article as done in Notion",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		ctx := context.Background()
+		article, err := svc.Find(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v: %s\n", err, args[0])
+			os.Exit(1)
+		}
+
+		if err := svc.MarkDone(ctx, article.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Marked as done: %s\n", article.Title)
+		reportQueued(ctx, svc)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doneCmd)
+}
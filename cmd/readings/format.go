@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"productivity.go/internal/readings"
+)
+
+// outputFormats lists the values accepted by --format.
+var outputFormats = []string{"table", "json", "tsv", "markdown"}
+
+// writeArticles prints articles in one of outputFormats.
+func writeArticles(w io.Writer, format string, articles []readings.Article) error {
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "TITLE\tTAGS\tURL")
+		for _, a := range articles {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", displayTitle(a), strings.Join(a.Tags, ", "), a.URL)
+		}
+		return tw.Flush()
+	case "json":
+		if articles == nil {
+			articles = []readings.Article{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(articles)
+	case "tsv":
+		for _, a := range articles {
+			fields := []string{a.ID, a.Title, a.URL, strings.Join(a.Tags, ",")}
+			for i, f := range fields {
+				fields[i] = tsvReplacer.Replace(f)
+			}
+			fmt.Fprintln(w, strings.Join(fields, "\t"))
+		}
+		return nil
+	case "markdown":
+		for _, a := range articles {
+			line := fmt.Sprintf("- [%s](%s)", markdownReplacer.Replace(displayTitle(a)), a.URL)
+			for _, t := range a.Tags {
+				line += fmt.Sprintf(" `%s`", t)
+			}
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(outputFormats, ", "))
+	}
+}
+
+var (
+	tsvReplacer      = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	markdownReplacer = strings.NewReplacer("[", `\[`, "]", `\]`)
+)
+
+func displayTitle(a readings.Article) string {
+	if a.Title == "" {
+		return "Untitled"
+	}
+	return a.Title
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/config"
+	"productivity.go/internal/notion/notiontest"
+	"productivity.go/internal/sync"
+)
+
+const (
+	testReadingDB = "a0e3e448792a4aa59f0d4576333457e9"
+	testWeeksDB   = "f291b0e4b2f64b7d818fe996318ecdf1"
+)
+
+// fakeHome points the CLI at a fresh home directory configured to talk to a
+// notiontest.Server with the default template's databases.
+func fakeHome(t *testing.T) *notiontest.Server {
+	t.Helper()
+
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Token = "secret"
+	srv.AddDatabase(testReadingDB, map[string]string{
+		"Name": "title",
+		"URL":  "url",
+		"Tags": "multi_select",
+		"Done": "checkbox",
+	})
+	srv.AddDatabase(testWeeksDB, map[string]string{
+		"Name":           "title",
+		"🗓️ Span":        "date",
+		"📑 Reading List": "relation",
+	})
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	// Queued changes are pushed by the syncs the tests run, not by a
+	// background process.
+	triggerSync = func() error { return nil }
+	t.Cleanup(func() { triggerSync = sync.TriggerBackgroundSync })
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".netrc"),
+		[]byte("machine notion.so login apikey password secret\n"), 0600))
+
+	configDir := filepath.Join(home, ".config", config.ConfigDirName)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, config.ConfigFileName), []byte(fmt.Sprintf(`
+notion_database_id = %q
+notion_weeks_db_id = %q
+
+[notion]
+base_url = %q
+requests_per_second = 1000
+`, testReadingDB, testWeeksDB, srv.URL)), 0644))
+
+	return srv
+}
+
+func runCLI(t *testing.T, args ...string) {
+	t.Helper()
+	t.Cleanup(func() { fullSyncFlag = false })
+	rootCmd.SetArgs(args)
+	require.NoError(t, rootCmd.Execute())
+}
+
+func addTestArticle(srv *notiontest.Server, title string, tags ...string) string {
+	return srv.AddPage(testReadingDB, notiontest.Properties{
+		"Name": notiontest.Title(title),
+		"URL":  notiontest.URL("https://example.com/" + title),
+		"Tags": notiontest.MultiSelect(tags...),
+		"Done": notiontest.Checkbox(false),
+	})
+}
+
+// cachedTitles returns the sorted titles in the local cache.
+func cachedTitles(t *testing.T) []string {
+	t.Helper()
+	svc, store, err := openService()
+	require.NoError(t, err)
+	defer store.Close()
+
+	articles, err := svc.GetAll(context.Background())
+	require.NoError(t, err)
+
+	titles := make([]string, len(articles))
+	for i, a := range articles {
+		titles[i] = a.Title
+	}
+	sort.Strings(titles)
+	return titles
+}
+
+func countRequests(srv *notiontest.Server, request string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r == request {
+			n++
+		}
+	}
+	return n
+}
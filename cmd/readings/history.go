@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+)
+
+var (
+	historyKindFlags   []string
+	historyArticleFlag string
+	historySinceFlag   string
+	historyUntilFlag   string
+	historyLimitFlag   int
+	historyFormatFlag  string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show what was suggested, planned, opened and marked done",
+	Long: `Lists the local history, newest first: articles shown by plans and
+picked lists, added to a week's reading list, opened from the TUI and marked
+done from this machine.`,
+	Example: `  readings history --kind done --since 2026-10-01
+  readings history --article https://go.dev/blog/intro-generics`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		ctx := context.Background()
+		filter, err := historyFilter()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		events, err := svc.History(ctx, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeHistory(os.Stdout, historyFormatFlag, events); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// historyFilter builds the filter from the flags. --until includes the
+// whole day.
+func historyFilter() (readings.HistoryFilter, error) {
+	filter := readings.HistoryFilter{Article: historyArticleFlag, Limit: historyLimitFlag}
+	for _, name := range historyKindFlags {
+		kind, err := readings.ParseEventKind(name)
+		if err != nil {
+			return filter, err
+		}
+		filter.Kinds = append(filter.Kinds, kind)
+	}
+
+	var err error
+	if filter.Since, err = parseDay("--since", historySinceFlag); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = parseDay("--until", historyUntilFlag); err != nil {
+		return filter, err
+	}
+	if !filter.Until.IsZero() {
+		filter.Until = filter.Until.AddDate(0, 0, 1)
+	}
+	return filter, nil
+}
+
+// writeHistory prints events as a table or JSON.
+func writeHistory(w io.Writer, format string, events []readings.Event) error {
+	switch format {
+	case "table":
+		if len(events) == 0 {
+			fmt.Fprintln(w, "No history yet.")
+			return nil
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "TIME\tEVENT\tARTICLE")
+		for _, e := range events {
+			title := e.Title
+			if title == "" {
+				title = e.ArticleID
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", e.At.Local().Format("2006-01-02 15:04"), describeEvent(e.Kind), title)
+		}
+		return tw.Flush()
+	case "json":
+		if events == nil {
+			events = []readings.Event{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	default:
+		return fmt.Errorf("unknown format %q (want table or json)", format)
+	}
+}
+
+func describeEvent(kind readings.EventKind) string {
+	switch kind {
+	case readings.EventShown:
+		return "shown"
+	case readings.EventPlanned:
+		return "added to week"
+	case readings.EventOpened:
+		return "opened"
+	case readings.EventDone:
+		return "marked done"
+	default:
+		return string(kind)
+	}
+}
+
+func init() {
+	historyCmd.Flags().StringSliceVarP(&historyKindFlags, "kind", "k", nil, "Only these events: shown, planned, opened, done (repeatable)")
+	historyCmd.Flags().StringVarP(&historyArticleFlag, "article", "a", "", "Only events of this article (ID or URL)")
+	historyCmd.Flags().StringVar(&historySinceFlag, "since", "", "Only events on or after this day (YYYY-MM-DD)")
+	historyCmd.Flags().StringVar(&historyUntilFlag, "until", "", "Only events on or before this day (YYYY-MM-DD)")
+	historyCmd.Flags().IntVarP(&historyLimitFlag, "limit", "n", 50, "Maximum number of events (0 for all)")
+	historyCmd.Flags().StringVarP(&historyFormatFlag, "format", "f", "table", "Output format: table, json")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/notion/notiontest"
+)
+
+func TestHistoryCommand(t *testing.T) {
+	srv := fakeHome(t)
+	t.Cleanup(func() {
+		weekAddFlags = nil
+		historyKindFlags, historyArticleFlag, historySinceFlag, historyUntilFlag = nil, "", "", ""
+	})
+
+	addTestArticle(srv, "alpha")
+	addTestArticle(srv, "beta")
+	today := time.Now()
+	srv.AddPage(testWeeksDB, notiontest.Properties{
+		"Name":    notiontest.Title(today.Format("2006-01-02")),
+		"🗓️ Span": notiontest.Date(today.AddDate(0, 0, -3).Format("2006-01-02"), today.AddDate(0, 0, 3).Format("2006-01-02")),
+	})
+	runCLI(t, "sync")
+	runCLI(t, "week", "--add", "https://example.com/alpha", "--add", "https://example.com/beta")
+	runCLI(t, "done", "https://example.com/alpha")
+
+	svc, store, err := openService()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	history := func() string {
+		filter, err := historyFilter()
+		require.NoError(t, err)
+		events, err := svc.History(ctx, filter)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		require.NoError(t, writeHistory(&out, "table", events))
+		return out.String()
+	}
+	assert.Regexp(t, `(?s)marked done\s+alpha\n.*added to week\s+beta\n.*added to week\s+alpha\n`, history())
+
+	// A done article is found by URL although it left the cache.
+	historyArticleFlag = "https://example.com/alpha"
+	historyKindFlags = []string{"planned"}
+	assert.Equal(t, 1, strings.Count(history(), "alpha"))
+
+	historyArticleFlag, historyKindFlags = "", nil
+	historySinceFlag = today.AddDate(0, 0, 1).Format("2006-01-02")
+	assert.Equal(t, "No history yet.\n", history())
+
+	historyKindFlags = []string{"read"}
+	_, err = historyFilter()
+	assert.ErrorContains(t, err, "unknown event")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+)
+
+var (
+	listTagFlags        []string
+	listExcludeTagFlags []string
+	listMatchAllFlag    bool
+	listLimitFlag       int
+	listRandomFlag      bool
+	listFormatFlag      string
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print cached articles for use in scripts",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		articles, err := svc.List(context.Background(), readings.ListOptions{
+			Tags: readings.TagFilter{
+				Include:  listTagFlags,
+				Exclude:  listExcludeTagFlags,
+				MatchAll: listMatchAllFlag,
+			},
+			Limit:  listLimitFlag,
+			Random: listRandomFlag || cmd.Flags().Changed("strategy"),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeArticles(os.Stdout, listFormatFlag, articles); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	listCmd.Flags().StringSliceVarP(&listTagFlags, "tag", "t", nil, "Only list articles with any of these tags (repeatable)")
+	listCmd.Flags().StringSliceVar(&listExcludeTagFlags, "exclude-tag", nil, "Skip articles with any of these tags (repeatable)")
+	listCmd.Flags().BoolVar(&listMatchAllFlag, "match-all", false, "Require all --tag values instead of any")
+	listCmd.Flags().IntVarP(&listLimitFlag, "limit", "n", 0, "Maximum number of articles (0 for all)")
+	listCmd.Flags().BoolVarP(&listRandomFlag, "random", "r", false, "Order with the selection strategy instead of by title")
+	addStrategyFlags(listCmd)
+	listCmd.Flags().StringVarP(&listFormatFlag, "format", "f", "table", "Output format: "+strings.Join(outputFormats, ", "))
+	rootCmd.AddCommand(listCmd)
+}
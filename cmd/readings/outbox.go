@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+	"productivity.go/internal/sync"
+)
+
+var (
+	outboxFormatFlag string
+	discardAllFlag   bool
+)
+
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Show changes waiting to be sent to Notion",
+	Long: `Changes made while Notion cannot be reached are queued and sent on the
+next sync. Changes Notion rejects are kept as failed until discarded.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		ctx := context.Background()
+		ops, err := svc.Outbox(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch outboxFormatFlag {
+		case "table":
+			if len(ops) == 0 {
+				fmt.Println("No pending changes.")
+				return
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tCHANGE\tARTICLE\tQUEUED\tSTATUS")
+			for _, op := range ops {
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", op.ID, describeOp(op), articleLabel(ctx, svc, op.ArticleID),
+					op.CreatedAt.Local().Format("2006-01-02 15:04"), opStatus(op))
+			}
+			tw.Flush()
+		case "json":
+			if ops == nil {
+				ops = []readings.PendingOp{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(ops)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown format %q (want table or json)\n", outboxFormatFlag)
+			os.Exit(1)
+		}
+	},
+}
+
+var outboxDiscardCmd = &cobra.Command{
+	Use:   "discard [id...]",
+	Short: "Drop queued changes without sending them",
+	Long: `Drop queued changes without sending them to Notion. The local cache
+keeps the discarded edits until 'readings sync --full' refetches it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if discardAllFlag == (len(args) > 0) {
+			fmt.Fprintln(os.Stderr, "Error: pass the IDs to discard or --all")
+			os.Exit(1)
+		}
+
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		ctx := context.Background()
+		var ids []int64
+		if discardAllFlag {
+			ops, err := svc.Outbox(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			for _, op := range ops {
+				ids = append(ids, op.ID)
+			}
+		}
+		for _, arg := range args {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid change ID %q\n", arg)
+				os.Exit(1)
+			}
+			ids = append(ids, id)
+		}
+
+		n, err := svc.Discard(ctx, ids)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Discarded %d change(s).\n", n)
+		if n > 0 {
+			fmt.Println("Run 'readings sync --full' to restore the local cache from Notion.")
+		}
+	},
+}
+
+// describeOp summarizes a queued change for humans.
+func describeOp(op readings.PendingOp) string {
+	switch op.Kind {
+	case readings.OpAddToWeek:
+		return "add to week"
+	case readings.OpRemoveFromWeek:
+		return "remove from week"
+	case readings.OpMarkDone:
+		return "mark done"
+	case readings.OpSetTags:
+		return "set tags: " + strings.Join(op.Tags, ", ")
+	case readings.OpMoveInWeek:
+		return fmt.Sprintf("move to #%d in week", op.Position+1)
+	default:
+		return string(op.Kind)
+	}
+}
+
+func opStatus(op readings.PendingOp) string {
+	switch {
+	case op.Failed:
+		return "failed: " + op.LastError
+	case op.Attempts > 0:
+		return fmt.Sprintf("retrying (%d attempts): %s", op.Attempts, op.LastError)
+	default:
+		return "pending"
+	}
+}
+
+// articleLabel returns the article's title, or its ID if it is no longer
+// cached, as after marking it done.
+func articleLabel(ctx context.Context, svc *readings.Service, id string) string {
+	article, err := svc.Find(ctx, id)
+	if err != nil {
+		return id
+	}
+	return displayTitle(*article)
+}
+
+// triggerSync starts a 'readings sync' in the background. Tests replace it.
+var triggerSync = sync.TriggerBackgroundSync
+
+// reportQueued tells the user when changes have not reached Notion yet and
+// starts a background sync to send them, so commands do not wait on Notion.
+func reportQueued(ctx context.Context, svc *readings.Service) {
+	ops, err := svc.Outbox(ctx)
+	if err != nil || len(ops) == 0 {
+		return
+	}
+	if err := triggerSync(); err != nil {
+		fmt.Fprintf(os.Stderr, "%d change(s) not yet in Notion; they are sent on the next sync (see 'readings outbox')\n", len(ops))
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%d change(s) not yet in Notion; sending them in the background (see 'readings outbox')\n", len(ops))
+}
+
+func init() {
+	outboxCmd.Flags().StringVarP(&outboxFormatFlag, "format", "f", "table", "Output format: table, json")
+	outboxDiscardCmd.Flags().BoolVar(&discardAllFlag, "all", false, "Discard every queued change")
+	outboxCmd.AddCommand(outboxDiscardCmd)
+	rootCmd.AddCommand(outboxCmd)
+}
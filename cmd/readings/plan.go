@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/config"
+	"productivity.go/internal/readings"
+)
+
+var (
+	planCountFlag      int
+	planBudgetFlag     time.Duration
+	planQuotaFlags     []string
+	planAvoidWeeksFlag int
+	planTagFlags       []string
+	planExcludeFlags   []string
+	planNextFlag       bool
+	planDateFlag       string
+	planYesFlag        bool
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Propose a reading list for a week and add it after confirmation",
+	Long: `Picks articles from the local cache for this week's reading list, or the
+week picked with --next or --date, and asks before adding them. Tag quotas
+are filled first, then other articles until the list holds --count articles
+or --budget of reading time; articles already planned count towards both.
+Articles on the reading lists of the --avoid-weeks weeks before are skipped.
+
+Defaults come from the [plan] section of the config file, with weekly_goal
+as the count when neither a count nor a budget is set.`,
+	Example: `  readings plan --next --count 5 --quota architecture=2 --quota security=1
+  readings plan --budget 3h --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		at, err := weekTarget(time.Now(), planDateFlag, planNextFlag, false)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts, err := planFlagOptions(cmd, planOptions(cfg))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		plan, err := svc.ProposePlan(ctx, at, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		writePlan(os.Stdout, plan)
+		if len(plan.Proposed) == 0 {
+			fmt.Println("Nothing to add.")
+			return
+		}
+		if !planYesFlag && !confirm(cmd.InOrStdin(), os.Stdout, "Add the proposed articles to the reading list?") {
+			fmt.Println("Plan discarded.")
+			return
+		}
+
+		if err := svc.ApplyPlan(ctx, plan); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Added %d article(s) to the reading list.\n", len(plan.Proposed))
+		reportQueued(ctx, svc)
+	},
+}
+
+// planOptions returns the plan defaults from the config file.
+func planOptions(cfg *config.Config) readings.PlanOptions {
+	opts := readings.PlanOptions{
+		Count:      cfg.Plan.Count,
+		Budget:     int(cfg.Plan.Budget / time.Minute),
+		Quotas:     cfg.Plan.Quotas,
+		AvoidWeeks: cfg.Plan.AvoidWeeks,
+	}
+	if opts.Count == 0 && opts.Budget == 0 {
+		opts.Count = cfg.WeeklyGoal
+	}
+	return opts
+}
+
+// planFlagOptions overrides opts with the flags given on the command line.
+// A count or budget flag replaces both configured limits.
+func planFlagOptions(cmd *cobra.Command, opts readings.PlanOptions) (readings.PlanOptions, error) {
+	flags := cmd.Flags()
+	if flags.Changed("count") || flags.Changed("budget") {
+		opts.Count = planCountFlag
+		opts.Budget = int(planBudgetFlag / time.Minute)
+	}
+	if flags.Changed("avoid-weeks") {
+		opts.AvoidWeeks = planAvoidWeeksFlag
+	}
+	if len(planQuotaFlags) > 0 {
+		quotas, err := parseQuotas(planQuotaFlags)
+		if err != nil {
+			return opts, err
+		}
+		opts.Quotas = quotas
+	}
+	opts.Tags = readings.TagFilter{Include: planTagFlags, Exclude: planExcludeFlags}
+
+	if opts.Count < 0 || opts.Budget < 0 || opts.AvoidWeeks < 0 {
+		return opts, fmt.Errorf("--count, --budget and --avoid-weeks must not be negative")
+	}
+	return opts, nil
+}
+
+// parseQuotas reads tag=count pairs.
+func parseQuotas(values []string) (map[string]int, error) {
+	quotas := make(map[string]int, len(values))
+	for _, v := range values {
+		tag, count, ok := strings.Cut(v, "=")
+		n, err := strconv.Atoi(count)
+		if !ok || strings.TrimSpace(tag) == "" || err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid --quota %q (want tag=count)", v)
+		}
+		quotas[strings.TrimSpace(tag)] = n
+	}
+	return quotas, nil
+}
+
+// writePlan prints the week's reading list with the proposed articles
+// marked with +.
+func writePlan(w io.Writer, plan readings.Plan) {
+	fmt.Fprintf(w, "Week of %s: %d planned, ~%s\n", weekSpan(plan.Week),
+		len(plan.Week.ReadingListIDs)+len(plan.Proposed), formatMinutes(plan.Minutes))
+	for _, a := range plan.Planned {
+		fmt.Fprintf(w, "  %s\n", describePlanned(a))
+	}
+	if missing := len(plan.Week.ReadingListIDs) - len(plan.Planned); missing > 0 {
+		fmt.Fprintf(w, "  and %d article(s) not in the cache\n", missing)
+	}
+	for _, a := range plan.Proposed {
+		fmt.Fprintf(w, "+ %s\n", describePlanned(a))
+	}
+}
+
+func describePlanned(a readings.Article) string {
+	s := displayTitle(a)
+	if a.ReadingMinutes > 0 {
+		s += fmt.Sprintf(" (%d min)", a.ReadingMinutes)
+	}
+	if len(a.Tags) > 0 {
+		tags := append([]string(nil), a.Tags...)
+		sort.Strings(tags)
+		s += " [" + strings.Join(tags, ", ") + "]"
+	}
+	return s
+}
+
+// formatMinutes prints a reading time such as "1h20m" or "45m".
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return strings.TrimSuffix((time.Duration(minutes) * time.Minute).String(), "0s")
+}
+
+// confirm asks a yes/no question on w and reads the answer from r. Anything
+// but y or yes, including no answer at all, means no.
+func confirm(r io.Reader, w io.Writer, question string) bool {
+	fmt.Fprintf(w, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func init() {
+	planCmd.Flags().IntVarP(&planCountFlag, "count", "n", 0, "Articles on the reading list when done")
+	planCmd.Flags().DurationVar(&planBudgetFlag, "budget", 0, "Total reading time of the list, such as 3h")
+	planCmd.Flags().StringArrayVar(&planQuotaFlags, "quota", nil, "Articles to pick with a tag, as tag=count (repeatable)")
+	planCmd.Flags().IntVar(&planAvoidWeeksFlag, "avoid-weeks", config.DefaultAvoidWeeks, "Skip articles planned in this many weeks before")
+	planCmd.Flags().StringSliceVarP(&planTagFlags, "tag", "t", nil, "Only pick articles with any of these tags (repeatable)")
+	planCmd.Flags().StringSliceVar(&planExcludeFlags, "exclude-tag", nil, "Never pick articles with any of these tags (repeatable)")
+	planCmd.Flags().BoolVar(&planNextFlag, "next", false, "Plan next week")
+	planCmd.Flags().StringVar(&planDateFlag, "date", "", "Plan the week containing this day (YYYY-MM-DD)")
+	addStrategyFlags(planCmd)
+	planCmd.Flags().BoolVarP(&planYesFlag, "yes", "y", false, "Add the proposed articles without asking")
+	rootCmd.AddCommand(planCmd)
+}
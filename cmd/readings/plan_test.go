@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/notion/notiontest"
+)
+
+func TestPlanCommand(t *testing.T) {
+	srv := fakeHome(t)
+	t.Cleanup(func() {
+		planCountFlag, planQuotaFlags, planYesFlag = 0, nil, false
+	})
+
+	planned := addTestArticle(srv, "planned", "go")
+	readLastWeek := addTestArticle(srv, "read last week", "security")
+	security := addTestArticle(srv, "security", "security")
+	for _, title := range []string{"one", "two", "three"} {
+		addTestArticle(srv, title)
+	}
+	now := time.Now()
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	monday := time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, time.Local)
+	addWeek := func(start time.Time, readingList ...string) string {
+		return srv.AddPage(testWeeksDB, notiontest.Properties{
+			"Name":           notiontest.Title(start.Format("2006-01-02")),
+			"🗓️ Span":        notiontest.Date(start.Format("2006-01-02"), start.AddDate(0, 0, 6).Format("2006-01-02")),
+			"📑 Reading List": notiontest.Relation(readingList...),
+		})
+	}
+	addWeek(monday.AddDate(0, 0, -7), readLastWeek)
+	week := addWeek(monday, planned)
+	runCLI(t, "sync")
+
+	runCLI(t, "plan", "--count", "3", "--quota", "security=1", "--yes")
+	runCLI(t, "sync")
+	page, ok := srv.Page(week)
+	require.True(t, ok)
+	readingList := notiontest.RelationIDs(page.Properties["📑 Reading List"])
+	require.Len(t, readingList, 3)
+	assert.Equal(t, []string{planned, security}, readingList[:2])
+	assert.NotContains(t, readingList, readLastWeek)
+	assert.Equal(t, 1, countRequests(srv, "PATCH /v1/pages/"+week), "The plan is written in one update")
+
+	_, err := parseQuotas([]string{"security"})
+	assert.ErrorContains(t, err, "tag=count")
+	assert.False(t, confirm(strings.NewReader(""), io.Discard, "Add?"))
+	assert.True(t, confirm(strings.NewReader("Y\n"), io.Discard, "Add?"))
+}
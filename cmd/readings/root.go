@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"sync/atomic"
 
 	"github.com/spf13/cobra"
 	"productivity.go/internal/config"
-	"productivity.go/internal/notion"
 	"productivity.go/internal/readings"
 	"productivity.go/internal/storage"
 	"productivity.go/internal/sync"
@@ -14,7 +15,9 @@ import (
 )
 
 var (
-	tagFlag string
+	tagFlags        []string
+	excludeTagFlags []string
+	matchAllFlag    bool
 )
 
 var rootCmd = &cobra.Command{
@@ -32,6 +35,12 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		svcOpts, err := serviceOptions(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
 		store, err := storage.NewSQLite()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
@@ -39,25 +48,69 @@ var rootCmd = &cobra.Command{
 		}
 		defer store.Close()
 
-		notionClient := notion.NewClient(cfg.NotionAPIKey, cfg.NotionDatabaseID, cfg.NotionWeeksDBID)
-		svc := readings.NewService(store, notionClient)
+		svc := readings.NewService(store, newNotionClient(cfg), svcOpts...)
 
-		// Launch TUI
-		if err := tui.Start(svc); err != nil {
+		// Launch TUI, syncing in the background under the same lock as
+		// 'readings sync'
+		var launchSynced atomic.Bool
+		syncFn := func(ctx context.Context) (readings.SyncResult, error) {
+			paths, err := sync.DefaultPaths()
+			if err != nil {
+				return readings.SyncResult{}, err
+			}
+			result, err := sync.Run(paths, false, func() (readings.SyncResult, error) {
+				return svc.Sync(ctx)
+			})
+			launchSynced.Store(err == nil)
+			return result, err
+		}
+		opts := tui.Options{
+			Filter:     readings.TagFilter{Include: tagFlags, Exclude: excludeTagFlags, MatchAll: matchAllFlag},
+			Sync:       syncFn,
+			WeeklyGoal: cfg.WeeklyGoal,
+			Plan:       planOptions(cfg),
+		}
+		if err := tui.Start(svc, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Trigger background sync
-		if err := sync.TriggerBackgroundSync(); err != nil {
+		// Trigger background sync, unless the launch sync left nothing
+		// to send
+		if !needsExitSync(context.Background(), svc, launchSynced.Load()) {
+			return
+		}
+		if err := triggerSync(); err != nil {
 			// Just log to stderr, don't fail the command
 			fmt.Fprintf(os.Stderr, "Failed to trigger background sync: %v\n", err)
 		}
 	},
 }
 
+// needsExitSync reports whether changes may be waiting for Notion after the
+// TUI closes: the launch sync failed or did not finish, or changes were
+// queued since. Changes Notion rejected are not sent again and do not count.
+func needsExitSync(ctx context.Context, svc *readings.Service, launchSynced bool) bool {
+	if !launchSynced {
+		return true
+	}
+	ops, err := svc.Outbox(ctx)
+	if err != nil {
+		return true
+	}
+	for _, op := range ops {
+		if !op.Failed {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
-	rootCmd.Flags().StringVarP(&tagFlag, "tag", "t", "", "Filter by tag")
+	rootCmd.Flags().StringSliceVarP(&tagFlags, "tag", "t", nil, "Only show articles with any of these tags (repeatable)")
+	rootCmd.Flags().StringSliceVar(&excludeTagFlags, "exclude-tag", nil, "Hide articles with any of these tags (repeatable)")
+	rootCmd.Flags().BoolVar(&matchAllFlag, "match-all", false, "Require all --tag values instead of any")
+	addStrategyFlags(rootCmd)
 	rootCmd.AddCommand(setupCmd)
 }
 
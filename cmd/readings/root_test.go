@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedsExitSync(t *testing.T) {
+	srv := fakeHome(t)
+	article := addTestArticle(srv, "alpha")
+	runCLI(t, "sync")
+
+	svc, store, err := openService()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	assert.True(t, needsExitSync(ctx, svc, false), "The launch sync failed or did not finish")
+	assert.False(t, needsExitSync(ctx, svc, true))
+
+	require.NoError(t, svc.MarkDone(ctx, article))
+	assert.True(t, needsExitSync(ctx, svc, true), "A change was queued after the launch sync")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchLimitFlag  int
+	searchFormatFlag string
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search cached articles by title, URL and tags",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		articles, err := svc.Search(context.Background(), strings.Join(args, " "), searchLimitFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeArticles(os.Stdout, searchFormatFlag, articles); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	searchCmd.Flags().IntVarP(&searchLimitFlag, "limit", "n", 20, "Maximum number of results")
+	searchCmd.Flags().StringVarP(&searchFormatFlag, "format", "f", "table", "Output format: "+strings.Join(outputFormats, ", "))
+	rootCmd.AddCommand(searchCmd)
+}
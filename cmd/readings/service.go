@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/config"
+	"productivity.go/internal/notion"
+	"productivity.go/internal/readings"
+	"productivity.go/internal/storage"
+)
+
+// openService loads the configuration and wires the local cache and the
+// Notion client into a readings.Service. The caller must close the store.
+func openService() (*readings.Service, *storage.SQLite, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w\nRun 'readings setup' to configure", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("configuration invalid: %w\nRun 'readings setup' to configure", err)
+	}
+
+	opts, err := serviceOptions(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := storage.NewSQLite()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	return readings.NewService(store, newNotionClient(cfg), opts...), store, nil
+}
+
+var (
+	strategyFlag string
+	seedFlag     int64
+)
+
+// addStrategyFlags adds --strategy and --seed to commands that pick articles.
+func addStrategyFlags(cmd *cobra.Command) {
+	var names []string
+	for _, s := range readings.Strategies() {
+		names = append(names, s.Name())
+	}
+	cmd.Flags().StringVar(&strategyFlag, "strategy", "", "How to pick articles: "+strings.Join(names, ", ")+" (default from config, else "+readings.DefaultStrategy+")")
+	cmd.Flags().Int64Var(&seedFlag, "seed", 0, "Seed for repeatable picks (0 for a different pick each run)")
+}
+
+// serviceOptions returns the selection strategy from --strategy or the
+// config file, seeded with --seed if given.
+func serviceOptions(cfg *config.Config) ([]readings.Option, error) {
+	name := cfg.Strategy
+	if strategyFlag != "" {
+		name = strategyFlag
+	}
+	strategy, err := readings.ParseStrategy(name)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []readings.Option{readings.WithStrategy(strategy)}
+	if seedFlag != 0 {
+		opts = append(opts, readings.WithSeed(seedFlag))
+	}
+	return opts, nil
+}
+
+// newNotionClient creates a Notion client for the configured databases.
+func newNotionClient(cfg *config.Config) *notion.Client {
+	policy := notion.DefaultRetryPolicy()
+	if cfg.RequestsPerSecond > 0 {
+		policy.RequestsPerSecond = cfg.RequestsPerSecond
+	}
+	if cfg.MaxRetries > 0 {
+		policy.MaxRetries = cfg.MaxRetries
+	}
+
+	opts := []notion.Option{
+		notion.WithProperties(cfg.Properties),
+		notion.WithRetryPolicy(policy),
+	}
+	if cfg.NotionBaseURL != "" {
+		opts = append(opts, notion.WithBaseURL(cfg.NotionBaseURL))
+	}
+	return notion.NewClient(cfg.NotionAPIKey, cfg.NotionDatabaseID, cfg.NotionWeeksDBID, opts...)
+}
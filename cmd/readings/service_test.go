@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/readings"
+)
+
+func TestStrategyFlags(t *testing.T) {
+	srv := fakeHome(t)
+	t.Cleanup(func() { strategyFlag, seedFlag = "", 0 })
+
+	added := time.Now().AddDate(-1, 0, 0)
+	for _, title := range []string{"oldest", "older", "newest"} {
+		srv.Now = func() time.Time { return added }
+		addTestArticle(srv, title)
+		added = added.AddDate(0, 1, 0)
+	}
+	runCLI(t, "sync")
+
+	pick := func(strategy string) []string {
+		strategyFlag, seedFlag = strategy, 42
+		svc, store, err := openService()
+		require.NoError(t, err)
+		defer store.Close()
+
+		articles, err := svc.List(context.Background(), readings.ListOptions{Limit: 2, Random: true})
+		require.NoError(t, err)
+		titles := make([]string, len(articles))
+		for i, a := range articles {
+			titles[i] = a.Title
+		}
+		return titles
+	}
+	assert.Equal(t, []string{"oldest", "older"}, pick("oldest"))
+	assert.Equal(t, "newest", pick("least-surfaced")[0], "Only the newest was never suggested")
+	assert.Equal(t, pick("uniform"), pick("uniform"), "--seed makes picks repeatable")
+
+	strategyFlag = "newest"
+	_, _, err := openService()
+	assert.ErrorContains(t, err, `unknown strategy "newest"`)
+}
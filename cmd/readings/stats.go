@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+)
+
+var (
+	statsWeeksFlag  int
+	statsTopFlag    int
+	statsFormatFlag string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report on the backlog, finished articles and planned weeks",
+	Long: `Reports, for the last --weeks calendar weeks, how many articles were
+saved, marked done and opened each week and how large the backlog was, how
+much of each week's reading list has been marked done since, and which tags
+are read most compared to how often they are saved.
+
+The report is built from the local cache and history, so articles finished
+before the first sync are not counted.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if statsWeeksFlag <= 0 || statsTopFlag < 0 {
+			fmt.Fprintln(os.Stderr, "Error: --weeks must be positive and --top must not be negative")
+			os.Exit(1)
+		}
+
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		stats, err := svc.Stats(context.Background(), time.Now(), readings.StatsOptions{Weeks: statsWeeksFlag, TopTags: statsTopFlag})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeStats(os.Stdout, statsFormatFlag, stats); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeStats prints the report as text or JSON.
+func writeStats(w io.Writer, format string, stats readings.Stats) error {
+	switch format {
+	case "text":
+		writeStatsText(w, stats)
+		return nil
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	default:
+		return fmt.Errorf("unknown format %q (want text or json)", format)
+	}
+}
+
+func writeStatsText(w io.Writer, stats readings.Stats) {
+	fmt.Fprintf(w, "Backlog: %d article(s)\n", stats.Backlog)
+	fmt.Fprintf(w, "Done in the last %d week(s): %d", len(stats.Weeks), stats.Completed)
+	if stats.AverageDays > 0 {
+		fmt.Fprintf(w, ", on average %.0f days after being saved", stats.AverageDays)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "WEEK OF\tSAVED\tDONE\tOPENED\tBACKLOG")
+	for _, ws := range stats.Weeks {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", ws.Start.Format("Mon Jan 2 2006"), ws.Added, ws.Completed, ws.Opened, ws.Backlog)
+	}
+	tw.Flush()
+
+	if len(stats.Planned) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "PLANNED WEEK\tPLANNED\tDONE\tRATE")
+		for _, pw := range stats.Planned {
+			rate := "-"
+			if pw.Planned > 0 {
+				rate = fmt.Sprintf("%.0f%%", pw.Rate*100)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", weekSpan(readings.Week{Start: pw.Start, End: pw.End}), pw.Planned, pw.Done, rate)
+		}
+		tw.Flush()
+	}
+
+	if len(stats.Tags) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "TAG\tREAD\tSAVED")
+		for _, ts := range stats.Tags {
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", ts.Tag, ts.Read, ts.Saved)
+		}
+		tw.Flush()
+	}
+}
+
+func init() {
+	statsCmd.Flags().IntVarP(&statsWeeksFlag, "weeks", "w", 8, "Calendar weeks to report, ending with this one")
+	statsCmd.Flags().IntVar(&statsTopFlag, "top", 10, "Tags to list (0 for all)")
+	statsCmd.Flags().StringVarP(&statsFormatFlag, "format", "f", "text", "Output format: text, json")
+	rootCmd.AddCommand(statsCmd)
+}
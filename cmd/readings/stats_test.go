@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/notion/notiontest"
+	"productivity.go/internal/readings"
+)
+
+func TestStatsCommand(t *testing.T) {
+	srv := fakeHome(t)
+	t.Cleanup(func() { weekAddFlags = nil })
+
+	addTestArticle(srv, "alpha", "go")
+	beta := addTestArticle(srv, "beta", "go")
+	gamma := addTestArticle(srv, "gamma", "rust")
+	today := time.Now()
+	srv.AddPage(testWeeksDB, notiontest.Properties{
+		"Name":    notiontest.Title(today.Format("2006-01-02")),
+		"🗓️ Span": notiontest.Date(today.AddDate(0, 0, -3).Format("2006-01-02"), today.AddDate(0, 0, 3).Format("2006-01-02")),
+	})
+	runCLI(t, "sync")
+	runCLI(t, "week", "--add", "https://example.com/alpha", "--add", "https://example.com/beta")
+	runCLI(t, "done", "https://example.com/alpha")
+
+	svc, store, err := openService()
+	require.NoError(t, err)
+	defer store.Close()
+
+	stats, err := svc.Stats(context.Background(), today, readings.StatsOptions{Weeks: 4, TopTags: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Backlog)
+	assert.Equal(t, 1, stats.Completed)
+	require.Len(t, stats.Weeks, 4)
+	require.Len(t, stats.Planned, 1)
+	assert.Equal(t, 0.5, stats.Planned[0].Rate)
+	assert.Equal(t, []readings.TagStats{{Tag: "go", Read: 1, Saved: 2}}, stats.Tags)
+
+	var out bytes.Buffer
+	require.NoError(t, writeStats(&out, "text", stats))
+	assert.Contains(t, out.String(), "Backlog: 2 article(s)\n")
+	assert.Contains(t, out.String(), "Done in the last 4 week(s): 1")
+	assert.Regexp(t, `\s2\s+1\s+50%\n`, out.String())
+	assert.Regexp(t, `go\s+1\s+2\n`, out.String())
+
+	out.Reset()
+	require.NoError(t, writeStats(&out, "json", stats))
+	assert.Contains(t, out.String(), `"planned_weeks"`)
+	assert.ErrorContains(t, writeStats(&out, "csv", stats), "unknown format")
+
+	// Pages ticked Done in Notion count as done after a full sync too;
+	// pages moved to the trash do not.
+	srv.SetProperty(beta, "Done", notiontest.Checkbox(true))
+	srv.Archive(gamma)
+	runCLI(t, "sync", "--full")
+	stats, err = svc.Stats(context.Background(), today, readings.StatsOptions{Weeks: 4, TopTags: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Backlog)
+	assert.Equal(t, 2, stats.Completed)
+	assert.Equal(t, 1.0, stats.Planned[0].Rate)
+}
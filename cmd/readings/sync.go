@@ -2,42 +2,270 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
-	"productivity.go/internal/config"
-	"productivity.go/internal/notion"
 	"productivity.go/internal/readings"
-	"productivity.go/internal/storage"
+	"productivity.go/internal/sync"
+)
+
+var (
+	fullSyncFlag     bool
+	syncStatusFlag   bool
+	syncDaemonFlag   bool
+	syncIntervalFlag time.Duration
+	forceInstallFlag bool
 )
 
 var syncCmd = &cobra.Command{
-	Use:    "sync",
-	Hidden: true,
-	Short:  "Synchronize articles from Notion to local cache",
+	Use:   "sync",
+	Short: "Synchronize articles from Notion to local cache",
+	Long: `Synchronize articles from Notion to the local cache. Only one sync runs
+at a time; each one is logged to sync.log next to the cache, and
+'readings sync --status' shows how the last one went.`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.Load()
+		paths, err := sync.DefaultPaths()
 		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		store, err := storage.NewSQLite()
-		if err != nil {
+		if syncStatusFlag {
+			if err := writeSyncStatus(os.Stdout, paths); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if cmd.Flags().Changed("interval") && !syncDaemonFlag {
+			fmt.Fprintln(os.Stderr, "Error: --interval only applies with --daemon or to install-service")
 			os.Exit(1)
 		}
-		defer store.Close()
 
-		notionClient := notion.NewClient(cfg.NotionAPIKey, cfg.NotionDatabaseID, cfg.NotionWeeksDBID)
-		svc := readings.NewService(store, notionClient)
+		if syncDaemonFlag {
+			if err := runSyncDaemon(paths, syncIntervalFlag); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 
-		if err := svc.Sync(context.Background()); err != nil {
-			fmt.Fprintf(os.Stderr, "Sync failed: %v\n", err)
+		if !syncOnce(context.Background(), paths, nil) {
 			os.Exit(1)
 		}
 	},
 }
 
+// syncOnce runs one sync under the sync lock and reports the outcome. svc
+// is opened for the sync if nil. It returns false if the sync failed.
+func syncOnce(ctx context.Context, paths sync.Paths, svc *readings.Service) bool {
+	result, err := sync.Run(paths, fullSyncFlag, func() (readings.SyncResult, error) {
+		if svc == nil {
+			opened, store, err := openService()
+			if err != nil {
+				return readings.SyncResult{}, err
+			}
+			defer store.Close()
+			svc = opened
+		}
+
+		if fullSyncFlag {
+			return svc.FullSync(ctx)
+		}
+		return svc.Sync(ctx)
+	})
+	if errors.Is(err, sync.ErrLocked) {
+		// The running sync does the work; this is not a failure.
+		fmt.Fprintln(os.Stderr, "Another sync is already running; skipped.")
+		return true
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Sync failed: %v\n", err)
+		return false
+	}
+	fmt.Printf("Synced: %s.\n", describeSync(result))
+	return true
+}
+
+// runSyncDaemon syncs every interval until SIGTERM or SIGINT. The first
+// signal lets a running sync finish; a second one cancels it.
+func runSyncDaemon(paths sync.Paths, interval time.Duration) error {
+	if interval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m, got %s", interval)
+	}
+
+	svc, store, err := openService()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	schedule, stop := context.WithCancel(context.Background())
+	defer stop()
+	syncCtx, cancelSync := context.WithCancel(context.Background())
+	defer cancelSync()
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		fmt.Println("Stopping after the current sync.")
+		stop()
+		<-signals
+		cancelSync()
+	}()
+
+	fmt.Printf("Syncing every %s.\n", interval)
+	sync.Every(schedule, interval, func() {
+		// Failures are logged and recorded; the next run may succeed.
+		syncOnce(syncCtx, paths, svc)
+	})
+	return nil
+}
+
+// writeSyncStatus prints the outcome of the last sync and whether one is
+// running now.
+func writeSyncStatus(w io.Writer, paths sync.Paths) error {
+	status, err := sync.LoadStatus(paths.Status)
+	if err != nil {
+		return err
+	}
+	pid, running, err := sync.Holder(paths.Lock)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if status == nil {
+		fmt.Fprintln(tw, "Last sync:\tnever")
+	} else {
+		kind := "incremental"
+		if status.Full {
+			kind = "full"
+		}
+		fmt.Fprintf(tw, "Last sync:\t%s (%s, took %s)\n", status.FinishedAt.Local().Format("2006-01-02 15:04:05"),
+			kind, status.Duration().Round(time.Millisecond))
+		if status.Error != "" {
+			fmt.Fprintf(tw, "Result:\tfailed: %s\n", status.Error)
+		} else {
+			fmt.Fprintf(tw, "Result:\t%s\n", describeSync(status.SyncResult))
+		}
+	}
+
+	switch {
+	case running && pid > 0:
+		fmt.Fprintf(tw, "Running:\tyes (pid %d)\n", pid)
+	case running:
+		fmt.Fprintln(tw, "Running:\tyes")
+	default:
+		fmt.Fprintln(tw, "Running:\tno")
+	}
+	fmt.Fprintf(tw, "Log:\t%s\n", paths.Log)
+	return tw.Flush()
+}
+
+var installServiceCmd = &cobra.Command{
+	Use:   "install-service",
+	Short: "Write a systemd user timer that syncs periodically",
+	Long: `Write a systemd user service and timer that run 'readings sync' with this
+executable every --interval. Enable them with:
+
+  systemctl --user daemon-reload
+  systemctl --user enable --now ` + sync.UnitName + `.timer`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := installService(syncIntervalFlag, forceInstallFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func installService(interval time.Duration, force bool) error {
+	if interval < time.Minute {
+		return fmt.Errorf("--interval must be at least 1m, got %s", interval)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	dir, err := systemdUserDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	service, timer := sync.SystemdUnits(exe, interval)
+	units := []struct{ path, content string }{
+		{filepath.Join(dir, sync.UnitName+".service"), service},
+		{filepath.Join(dir, sync.UnitName+".timer"), timer},
+	}
+
+	// Check both before writing either, so a refusal leaves no half install.
+	if !force {
+		for _, u := range units {
+			existing, err := os.ReadFile(u.path)
+			if err == nil && string(existing) != u.content {
+				return fmt.Errorf("%s already exists with different contents; pass --force to replace it", u.path)
+			}
+		}
+	}
+	for _, u := range units {
+		if err := os.WriteFile(u.path, []byte(u.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", u.path, err)
+		}
+		fmt.Printf("Wrote %s\n", u.path)
+	}
+
+	fmt.Printf("\nEnable the timer with:\n\n  systemctl --user daemon-reload\n  systemctl --user enable --now %s.timer\n", sync.UnitName)
+	return nil
+}
+
+// systemdUserDir returns where systemd looks for units of the user.
+func systemdUserDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home dir: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+func describeSync(r readings.SyncResult) string {
+	desc := fmt.Sprintf("fetched %d, removed %d, pushed %d", r.Fetched, r.Removed, r.Pushed)
+	if r.PushError != "" {
+		desc += "; " + r.PushError
+	}
+	return desc
+}
+
 func init() {
+	syncCmd.Flags().BoolVar(&fullSyncFlag, "full", false, "Refetch every article instead of only those edited since the last sync")
+	syncCmd.Flags().BoolVar(&syncStatusFlag, "status", false, "Show the result of the last sync instead of syncing")
+	syncCmd.Flags().BoolVar(&syncDaemonFlag, "daemon", false, "Keep running and sync every --interval until stopped")
+	syncCmd.Flags().DurationVar(&syncIntervalFlag, "interval", 15*time.Minute, "Time between syncs with --daemon")
+	installServiceCmd.Flags().DurationVar(&syncIntervalFlag, "interval", 15*time.Minute, "Time between syncs")
+	installServiceCmd.Flags().BoolVar(&forceInstallFlag, "force", false, "Replace existing units with different contents")
+	syncCmd.AddCommand(installServiceCmd)
 	rootCmd.AddCommand(syncCmd)
 }
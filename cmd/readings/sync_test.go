@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/notion/notiontest"
+	"productivity.go/internal/sync"
+)
+
+func TestSyncCommand(t *testing.T) {
+	srv := fakeHome(t)
+	srv.PageSize = 2
+
+	addTestArticle(srv, "alpha", "go")
+	finished := addTestArticle(srv, "beta")
+	deleted := addTestArticle(srv, "gamma")
+	srv.AddPage(testReadingDB, notiontest.Properties{
+		"Name": notiontest.Title("already done"),
+		"Done": notiontest.Checkbox(true),
+	})
+
+	runCLI(t, "sync")
+	assert.Equal(t, []string{"alpha", "beta", "gamma"}, cachedTitles(t))
+
+	// Edits made in Notion after the first sync.
+	time.Sleep(10 * time.Millisecond)
+	srv.SetProperty(finished, "Done", notiontest.Checkbox(true))
+	srv.Archive(deleted)
+	addTestArticle(srv, "delta")
+
+	runCLI(t, "sync")
+	assert.Equal(t, []string{"alpha", "delta", "gamma"}, cachedTitles(t),
+		"An incremental sync drops done pages but cannot see deleted ones")
+
+	runCLI(t, "sync", "--full")
+	assert.Equal(t, []string{"alpha", "delta"}, cachedTitles(t))
+
+	paths, err := sync.DefaultPaths()
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, writeSyncStatus(&out, paths))
+	assert.Contains(t, out.String(), "(full, took")
+	assert.Contains(t, out.String(), "fetched 2, removed 1, pushed 0")
+	assert.Regexp(t, `Running:\s+no\n`, out.String())
+}
+
+func TestInstallService(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir, err := systemdUserDir()
+	require.NoError(t, err)
+
+	require.NoError(t, installService(30*time.Minute, false))
+	timer, err := os.ReadFile(filepath.Join(dir, "readings-sync.timer"))
+	require.NoError(t, err)
+	assert.Contains(t, string(timer), "OnUnitActiveSec=1800s")
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	service, err := os.ReadFile(filepath.Join(dir, "readings-sync.service"))
+	require.NoError(t, err)
+	assert.Contains(t, string(service), filepath.Base(exe)+" sync\n")
+
+	// Rewriting the same units is fine; replacing different ones needs --force.
+	require.NoError(t, installService(30*time.Minute, false))
+	assert.ErrorContains(t, installService(time.Hour, false), "--force")
+	require.NoError(t, installService(time.Hour, true))
+
+	assert.ErrorContains(t, installService(time.Second, true), "at least 1m")
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+)
+
+var (
+	tagAddFlags    []string
+	tagRemoveFlags []string
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <id|url>",
+	Short: "Add or remove tags of an article",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(tagAddFlags) == 0 && len(tagRemoveFlags) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: pass --add or --remove")
+			os.Exit(1)
+		}
+
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		ctx := context.Background()
+		article, err := svc.Find(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v: %s\n", err, args[0])
+			os.Exit(1)
+		}
+
+		tags := editTags(article.Tags, tagAddFlags, tagRemoveFlags)
+		if err := svc.SetTags(ctx, article.ID, tags); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Tagged %s: %s\n", displayTitle(*article), strings.Join(tags, ", "))
+		reportQueued(ctx, svc)
+	},
+}
+
+// editTags removes and then adds tags, ignoring case and keeping the order
+// of the existing ones.
+func editTags(tags, add, remove []string) []string {
+	result := make([]string, 0, len(tags)+len(add))
+	has := func(tag string) bool {
+		for _, t := range result {
+			if readings.TagKey(t) == readings.TagKey(tag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, t := range tags {
+		removed := false
+		for _, r := range remove {
+			removed = removed || readings.TagKey(t) == readings.TagKey(r)
+		}
+		if !removed && !has(t) {
+			result = append(result, t)
+		}
+	}
+	for _, t := range add {
+		if t = strings.TrimSpace(t); t != "" && !has(t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func init() {
+	tagCmd.Flags().StringSliceVarP(&tagAddFlags, "add", "a", nil, "Tags to add (repeatable)")
+	tagCmd.Flags().StringSliceVarP(&tagRemoveFlags, "remove", "r", nil, "Tags to remove (repeatable)")
+	rootCmd.AddCommand(tagCmd)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+)
+
+var tagsFormatFlag string
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Print cached tags with their article counts",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		counts, err := svc.TagCounts(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeTags(os.Stdout, tagsFormatFlag, counts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeTags prints tag counts as a table or JSON.
+func writeTags(w io.Writer, format string, counts []readings.TagCount) error {
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "TAG\tARTICLES")
+		for _, c := range counts {
+			fmt.Fprintf(tw, "%s\t%d\n", c.Tag, c.Count)
+		}
+		return tw.Flush()
+	case "json":
+		if counts == nil {
+			counts = []readings.TagCount{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(counts)
+	default:
+		return fmt.Errorf("unknown format %q (want table or json)", format)
+	}
+}
+
+func init() {
+	tagsCmd.Flags().StringVarP(&tagsFormatFlag, "format", "f", "table", "Output format: table, json")
+	rootCmd.AddCommand(tagsCmd)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"productivity.go/internal/readings"
+)
+
+var (
+	weekNextFlag    bool
+	weekPrevFlag    bool
+	weekDateFlag    string
+	weekListFlag    bool
+	weekAddFlags    []string
+	weekRemoveFlags []string
+)
+
+var weekCmd = &cobra.Command{
+	Use:   "week",
+	Short: "Show or plan the reading list of a week",
+	Long: `Prints the reading list of the current week, or of the week picked with
+--date, --next and --prev. --next and --prev count from --date when both are
+given. Dates are days in the local time zone.`,
+	Example: `  readings week --next --add https://go.dev/blog/intro-generics
+  readings week --date 2026-10-26
+  readings week --list`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		at, err := weekTarget(time.Now(), weekDateFlag, weekNextFlag, weekPrevFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		svc, store, err := openService()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer store.Close()
+
+		ctx := context.Background()
+		if weekListFlag {
+			weeks, err := svc.ListWeeks(ctx, at.AddDate(0, 0, -28), at.AddDate(0, 0, 28))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			writeWeeks(os.Stdout, weeks, at)
+			return
+		}
+
+		changed, err := planWeek(ctx, svc, at, weekAddFlags, weekRemoveFlags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		week, err := svc.WeekAt(ctx, at)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		writeWeek(os.Stdout, week, func(id string) string { return articleLabel(ctx, svc, id) })
+		if changed {
+			reportQueued(ctx, svc)
+		}
+	},
+}
+
+// weekTarget returns a time in the week the flags pick: noon on date, or
+// today, moved by a week for next or prev.
+func weekTarget(now time.Time, date string, next, prev bool) (time.Time, error) {
+	day, err := parseDay("--date", date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if day.IsZero() {
+		day = now
+	}
+
+	// Noon is inside the day even where midnight is skipped for DST.
+	at := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.Local)
+	switch {
+	case next:
+		at = at.AddDate(0, 0, 7)
+	case prev:
+		at = at.AddDate(0, 0, -7)
+	}
+	return at, nil
+}
+
+// parseDay reads a YYYY-MM-DD flag as local midnight. An empty value is the
+// zero time.
+func parseDay(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q (want YYYY-MM-DD)", flag, value)
+	}
+	return day, nil
+}
+
+// planWeek adds and removes articles, given by ID or URL, from the reading
+// list of the week containing at. It reports whether anything changed.
+func planWeek(ctx context.Context, svc *readings.Service, at time.Time, add, remove []string) (bool, error) {
+	changed := false
+	edit := func(ref string, want bool) error {
+		article, err := svc.Find(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, ref)
+		}
+		week, err := svc.WeekAt(ctx, at)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(week.ReadingListIDs, article.ID) == want {
+			return nil
+		}
+		if _, err := svc.ToggleReadingInWeekAt(ctx, at, article.ID); err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	}
+
+	for _, ref := range add {
+		if err := edit(ref, true); err != nil {
+			return changed, err
+		}
+	}
+	for _, ref := range remove {
+		if err := edit(ref, false); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// writeWeek prints the week's span and its reading list in order, naming
+// articles with label.
+func writeWeek(w io.Writer, week readings.Week, label func(id string) string) {
+	fmt.Fprintf(w, "Week of %s: %d planned\n", weekSpan(week), len(week.ReadingListIDs))
+	for i, id := range week.ReadingListIDs {
+		fmt.Fprintf(w, "%d. %s\n", i+1, label(id))
+	}
+}
+
+// writeWeeks prints one line per week, marking the one containing at.
+func writeWeeks(w io.Writer, weeks []readings.Week, at time.Time) {
+	if len(weeks) == 0 {
+		fmt.Fprintln(w, "No weeks found.")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "WEEK\tPLANNED\t")
+	for _, week := range weeks {
+		marker := ""
+		if week.Contains(at) {
+			marker = "←"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", weekSpan(week), len(week.ReadingListIDs), marker)
+	}
+	tw.Flush()
+}
+
+func weekSpan(week readings.Week) string {
+	start, end := week.Start.Local(), week.End.Local()
+	return fmt.Sprintf("%s – %s", start.Format("Mon Jan 2"), end.Format("Mon Jan 2 2006"))
+}
+
+func init() {
+	weekCmd.Flags().BoolVar(&weekNextFlag, "next", false, "Use the week after")
+	weekCmd.Flags().BoolVar(&weekPrevFlag, "prev", false, "Use the week before")
+	weekCmd.Flags().StringVar(&weekDateFlag, "date", "", "Use the week containing this day (YYYY-MM-DD)")
+	weekCmd.Flags().BoolVar(&weekListFlag, "list", false, "List the weeks from four weeks before to four weeks after instead")
+	weekCmd.Flags().StringArrayVar(&weekAddFlags, "add", nil, "Add the article with this ID or URL to the reading list (repeatable)")
+	weekCmd.Flags().StringArrayVar(&weekRemoveFlags, "remove", nil, "Remove the article with this ID or URL from the reading list (repeatable)")
+	weekCmd.MarkFlagsMutuallyExclusive("next", "prev")
+	weekCmd.MarkFlagsMutuallyExclusive("list", "add")
+	weekCmd.MarkFlagsMutuallyExclusive("list", "remove")
+	rootCmd.AddCommand(weekCmd)
+}
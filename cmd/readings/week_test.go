@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/notion/notiontest"
+)
+
+func TestToggleReadingInCurrentWeek(t *testing.T) {
+	srv := fakeHome(t)
+
+	article := addTestArticle(srv, "alpha")
+	other := addTestArticle(srv, "beta")
+	today := time.Now().UTC()
+	week := srv.AddPage(testWeeksDB, notiontest.Properties{
+		"Name":           notiontest.Title(today.Format("2006-01-02")),
+		"🗓️ Span":        notiontest.Date(today.AddDate(0, 0, -3).Format("2006-01-02"), today.AddDate(0, 0, 3).Format("2006-01-02")),
+		"📑 Reading List": notiontest.Relation(other),
+	})
+	runCLI(t, "sync")
+
+	svc, store, err := openService()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	readingList := func() []string {
+		page, ok := srv.Page(week)
+		require.True(t, ok)
+		return notiontest.RelationIDs(page.Properties["📑 Reading List"])
+	}
+
+	added, err := svc.ToggleReadingInCurrentWeek(ctx, article)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []string{other}, readingList(), "Changes wait for a sync")
+	_, err = svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{other, article}, readingList())
+
+	added, err = svc.ToggleReadingInCurrentWeek(ctx, article)
+	require.NoError(t, err)
+	assert.False(t, added)
+	_, err = svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{other}, readingList())
+
+	ops, err := svc.Outbox(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, ops, "Every change reached Notion")
+}
+
+func TestWeekCommand(t *testing.T) {
+	srv := fakeHome(t)
+	t.Cleanup(func() {
+		weekNextFlag, weekPrevFlag, weekListFlag = false, false, false
+		weekDateFlag, weekAddFlags, weekRemoveFlags = "", nil, nil
+	})
+
+	article := addTestArticle(srv, "alpha")
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	addWeek := func(start time.Time) string {
+		return srv.AddPage(testWeeksDB, notiontest.Properties{
+			"Name":    notiontest.Title(start.Format("2006-01-02")),
+			"🗓️ Span": notiontest.Date(start.Format("2006-01-02"), start.AddDate(0, 0, 6).Format("2006-01-02")),
+		})
+	}
+	current := addWeek(monday)
+	next := addWeek(monday.AddDate(0, 0, 7))
+	runCLI(t, "sync")
+
+	// Sunday's --next is the following week.
+	runCLI(t, "week", "--date", "2026-10-25", "--next", "--add", "https://example.com/alpha")
+	runCLI(t, "sync")
+	readingList := func(id string) []string {
+		page, ok := srv.Page(id)
+		require.True(t, ok)
+		return notiontest.RelationIDs(page.Properties["📑 Reading List"])
+	}
+	assert.Equal(t, []string{article}, readingList(next))
+	assert.Empty(t, readingList(current))
+
+	svc, store, err := openService()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+	weeks, err := svc.ListWeeks(ctx, monday, monday.AddDate(0, 0, 14))
+	require.NoError(t, err)
+	var out bytes.Buffer
+	writeWeeks(&out, weeks, monday.AddDate(0, 0, 8))
+	assert.Regexp(t, `Mon Oct 19 – Sun Oct 25 2026\s+0\s*\n`, out.String())
+	assert.Regexp(t, `Mon Oct 26 – Sun Nov 1 2026\s+1\s+←\n`, out.String())
+
+	_, err = weekTarget(time.Now(), "26/10/2026", false, false)
+	assert.ErrorContains(t, err, "YYYY-MM-DD")
+}
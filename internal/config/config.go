@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/bgentry/go-netrc/netrc"
 	"github.com/spf13/viper"
@@ -21,11 +22,123 @@ type Config struct {
 	NotionAPIKey     string
 	NotionDatabaseID string
 	NotionWeeksDBID  string
+	Properties       NotionProperties
+
+	// Notion API limits from the [notion] section; 0 keeps the default.
+	RequestsPerSecond float64
+	MaxRetries        int
+
+	// WeeklyGoal is the number of articles to plan per week; 0 means none.
+	WeeklyGoal int
+
+	// Plan holds the defaults of 'readings plan'.
+	Plan PlanConfig
+
+	// Strategy names how articles are picked, see readings.ParseStrategy;
+	// empty means readings.DefaultStrategy.
+	Strategy string
+
+	// NotionBaseURL replaces https://api.notion.com, for testing against a
+	// fake server.
+	NotionBaseURL string
+}
+
+// NotionProperties maps each field the app reads or writes to the name of the
+// Notion property holding it. It is configured in the [notion.properties]
+// section of productivity.go.toml; missing keys keep their defaults.
+type NotionProperties struct {
+	// Reading database
+	Title string `mapstructure:"title"`
+	URL   string `mapstructure:"url"`
+	Tags  string `mapstructure:"tags"`
+	Done  string `mapstructure:"done"`
+
+	// Optional reading database properties; an empty name or a missing
+	// column leaves the field blank.
+	Notes       string `mapstructure:"notes"`
+	ReadingTime string `mapstructure:"reading_time"` // Number of minutes
+	Priority    string `mapstructure:"priority"`     // Number; higher is more urgent
+
+	// Weeks database
+	WeekName        string `mapstructure:"week_name"`
+	WeekSpan        string `mapstructure:"week_span"`
+	WeekReadingList string `mapstructure:"week_reading_list"`
+}
+
+// PlanConfig is read from the [plan] section of productivity.go.toml. A plan
+// needs a count or a budget; without either, weekly_goal is the count.
+type PlanConfig struct {
+	Count      int            `mapstructure:"count"`       // Articles on the reading list
+	Budget     time.Duration  `mapstructure:"budget"`      // Total reading time, such as "3h"
+	Quotas     map[string]int `mapstructure:"quotas"`      // Articles per tag
+	AvoidWeeks int            `mapstructure:"avoid_weeks"` // Skip articles planned in this many weeks before
+}
+
+// DefaultAvoidWeeks is how many past weeks a plan avoids unless configured.
+const DefaultAvoidWeeks = 4
+
+// DefaultProperties returns the property names of the original Notion template.
+func DefaultProperties() NotionProperties {
+	return NotionProperties{
+		Title:           "Name",
+		URL:             "URL",
+		Tags:            "Tags",
+		Done:            "Done",
+		Notes:           "Notes",
+		ReadingTime:     "Reading Time",
+		Priority:        "Priority",
+		WeekName:        "Name",
+		WeekSpan:        "🗓️ Span",
+		WeekReadingList: "📑 Reading List",
+	}
+}
+
+// Validate checks that every required property is named and that no two
+// fields of the same database share a property.
+func (p NotionProperties) Validate() error {
+	type field struct {
+		key, name string
+		optional  bool
+	}
+	reading := []field{
+		{"title", p.Title, false},
+		{"url", p.URL, false},
+		{"tags", p.Tags, false},
+		{"done", p.Done, false},
+		{"notes", p.Notes, true},
+		{"reading_time", p.ReadingTime, true},
+		{"priority", p.Priority, true},
+	}
+	weeks := []field{
+		{"week_name", p.WeekName, false},
+		{"week_span", p.WeekSpan, false},
+		{"week_reading_list", p.WeekReadingList, false},
+	}
+
+	for _, fields := range [][]field{reading, weeks} {
+		seen := make(map[string]string)
+		for _, f := range fields {
+			if strings.TrimSpace(f.name) == "" {
+				if f.optional {
+					continue
+				}
+				return fmt.Errorf("notion.properties.%s must not be empty", f.key)
+			}
+			if other, ok := seen[f.name]; ok {
+				return fmt.Errorf("notion.properties.%s and notion.properties.%s both use %q", other, f.key, f.name)
+			}
+			seen[f.name] = f.key
+		}
+	}
+	return nil
 }
 
 // Load reads configuration from .netrc and productivity.go.toml
 func Load() (*Config, error) {
-	cfg := &Config{}
+	cfg := &Config{
+		Properties: DefaultProperties(),
+		Plan:       PlanConfig{AvoidWeeks: DefaultAvoidWeeks},
+	}
 
 	// 1. Load Notion Database ID from TOML
 	if err := loadViperConfig(cfg); err != nil {
@@ -63,16 +176,38 @@ func loadViperConfig(cfg *Config) error {
 
 	cfg.NotionDatabaseID = CleanDatabaseID(viper.GetString("notion_database_id"))
 	cfg.NotionWeeksDBID = CleanDatabaseID(viper.GetString("notion_weeks_db_id"))
+
+	if err := viper.UnmarshalKey("notion.properties", &cfg.Properties); err != nil {
+		return fmt.Errorf("invalid [notion.properties]: %w", err)
+	}
+
+	cfg.RequestsPerSecond = viper.GetFloat64("notion.requests_per_second")
+	cfg.MaxRetries = viper.GetInt("notion.max_retries")
+	cfg.NotionBaseURL = viper.GetString("notion.base_url")
+	cfg.WeeklyGoal = viper.GetInt("weekly_goal")
+	cfg.Strategy = viper.GetString("strategy")
+
+	if err := viper.UnmarshalKey("plan", &cfg.Plan); err != nil {
+		return fmt.Errorf("invalid [plan]: %w", err)
+	}
 	return nil
 }
 
-func loadNetrcConfig(cfg *Config) error {
+// NetrcPath returns the location of the .netrc file holding the API key.
+func NetrcPath() (string, error) {
 	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".netrc"), nil
+}
+
+func loadNetrcConfig(cfg *Config) error {
+	netrcPath, err := NetrcPath()
 	if err != nil {
 		return err
 	}
 
-	netrcPath := filepath.Join(home, ".netrc")
 	// Check if file exists
 	if _, err := os.Stat(netrcPath); os.IsNotExist(err) {
 		return nil // No .netrc, that's fine for now
@@ -102,6 +237,30 @@ func (c *Config) Validate() error {
 	if c.NotionWeeksDBID == "" {
 		return fmt.Errorf("Notion Weeks Database ID not found in %s", ConfigFileName)
 	}
+	if err := c.Properties.Validate(); err != nil {
+		return fmt.Errorf("invalid property mapping in %s: %w", ConfigFileName, err)
+	}
+	if c.NotionBaseURL != "" {
+		// An unusable URL must not fall back to the real API with the key.
+		u, err := url.Parse(c.NotionBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("notion.base_url in %s must be an http or https URL, got %q", ConfigFileName, c.NotionBaseURL)
+		}
+	}
+	if c.RequestsPerSecond < 0 || c.MaxRetries < 0 {
+		return fmt.Errorf("notion.requests_per_second and notion.max_retries in %s must not be negative", ConfigFileName)
+	}
+	if c.WeeklyGoal < 0 {
+		return fmt.Errorf("weekly_goal in %s must not be negative", ConfigFileName)
+	}
+	if c.Plan.Count < 0 || c.Plan.Budget < 0 || c.Plan.AvoidWeeks < 0 {
+		return fmt.Errorf("plan.count, plan.budget and plan.avoid_weeks in %s must not be negative", ConfigFileName)
+	}
+	for tag, n := range c.Plan.Quotas {
+		if n < 0 {
+			return fmt.Errorf("plan.quotas.%s in %s must not be negative", tag, ConfigFileName)
+		}
+	}
 	return nil
 }
 
@@ -117,11 +276,10 @@ func Save(apiKey, databaseID, weeksDBID string) error {
 }
 
 func saveNetrc(apiKey string) error {
-	home, err := os.UserHomeDir()
+	netrcPath, err := NetrcPath()
 	if err != nil {
 		return err
 	}
-	netrcPath := filepath.Join(home, ".netrc")
 
 	var n *netrc.Netrc
 	if _, err := os.Stat(netrcPath); os.IsNotExist(err) {
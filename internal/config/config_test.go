@@ -1,9 +1,14 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCleanDatabaseID(t *testing.T) {
@@ -41,3 +46,103 @@ func TestCleanDatabaseID(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_PropertyMapping(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	configDir := filepath.Join(home, ".config", ConfigDirName)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, ConfigFileName), []byte(`
+notion_database_id = "a0e3e448792a4aa59f0d4576333457e9"
+notion_weeks_db_id = "f291b0e4b2f64b7d818fe996318ecdf1"
+weekly_goal = 5
+strategy = "age-weighted"
+
+[notion]
+requests_per_second = 2.5
+max_retries = 8
+base_url = "http://127.0.0.1:8080"
+
+[notion.properties]
+title = "Titel"
+tags = "Schlagworte"
+week_span = "Zeitraum"
+
+[plan]
+budget = "3h30m"
+quotas = { architecture = 2, security = 1 }
+`), 0644))
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	expected := DefaultProperties()
+	expected.Title = "Titel"
+	expected.Tags = "Schlagworte"
+	expected.WeekSpan = "Zeitraum"
+	assert.Equal(t, expected, cfg.Properties)
+	assert.Equal(t, 2.5, cfg.RequestsPerSecond)
+	assert.Equal(t, 8, cfg.MaxRetries)
+	assert.Equal(t, "http://127.0.0.1:8080", cfg.NotionBaseURL)
+	assert.Equal(t, 5, cfg.WeeklyGoal)
+	assert.Equal(t, "age-weighted", cfg.Strategy)
+	assert.Equal(t, PlanConfig{
+		Budget:     3*time.Hour + 30*time.Minute,
+		Quotas:     map[string]int{"architecture": 2, "security": 1},
+		AvoidWeeks: DefaultAvoidWeeks,
+	}, cfg.Plan)
+}
+
+func TestLoad_DefaultPropertiesWithoutConfigFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultProperties(), cfg.Properties)
+}
+
+func TestNotionProperties_Validate(t *testing.T) {
+	assert.NoError(t, DefaultProperties().Validate())
+
+	empty := DefaultProperties()
+	empty.URL = " "
+	assert.ErrorContains(t, empty.Validate(), "notion.properties.url")
+
+	duplicate := DefaultProperties()
+	duplicate.Tags = duplicate.Title
+	assert.ErrorContains(t, duplicate.Validate(), `both use "Name"`)
+
+	optional := DefaultProperties()
+	optional.Notes = ""
+	optional.ReadingTime = ""
+	optional.Priority = ""
+	assert.NoError(t, optional.Validate())
+
+	// The weeks database may reuse names from the reading database.
+	shared := DefaultProperties()
+	shared.WeekName = shared.Title
+	assert.NoError(t, shared.Validate())
+}
+
+func TestConfig_ValidateBaseURL(t *testing.T) {
+	cfg := Config{
+		NotionAPIKey:     "secret",
+		NotionDatabaseID: "reading",
+		NotionWeeksDBID:  "weeks",
+		Properties:       DefaultProperties(),
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.NotionBaseURL = "http://127.0.0.1:8080"
+	assert.NoError(t, cfg.Validate())
+
+	for _, bad := range []string{"127.0.0.1:8080", "localhost", "http//typo", "ftp://example.com"} {
+		cfg.NotionBaseURL = bad
+		assert.ErrorContains(t, cfg.Validate(), "notion.base_url", bad)
+	}
+}
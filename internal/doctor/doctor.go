@@ -0,0 +1,282 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jomei/notionapi"
+	"productivity.go/internal/config"
+	"productivity.go/internal/storage"
+)
+
+// Status is the outcome of a single check.
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+	Skip
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "✓"
+	case Warn:
+		return "!"
+	case Fail:
+		return "✗"
+	default:
+		return "-"
+	}
+}
+
+// Check is one line of the report.
+type Check struct {
+	Name   string
+	Status Status
+	Detail string
+	Hint   string // How to fix a warning or failure
+}
+
+// Report collects the results of all checks.
+type Report struct {
+	Checks []Check
+}
+
+// OK reports whether no check failed.
+func (r Report) OK() bool {
+	for _, c := range r.Checks {
+		if c.Status == Fail {
+			return false
+		}
+	}
+	return true
+}
+
+// Write prints the report, one check per line followed by its hint.
+func (r Report) Write(w io.Writer) {
+	width := 0
+	for _, c := range r.Checks {
+		if len(c.Name) > width {
+			width = len(c.Name)
+		}
+	}
+
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "%s %-*s  %s\n", c.Status, width, c.Name, c.Detail)
+		if c.Hint != "" && (c.Status == Warn || c.Status == Fail) {
+			fmt.Fprintf(w, "  %*s  → %s\n", width, "", c.Hint)
+		}
+	}
+}
+
+// SchemaReader retrieves database schemas from Notion.
+type SchemaReader interface {
+	PropertyTypes(ctx context.Context, databaseID string) (map[string]string, error)
+}
+
+// Env is what the checks run against.
+type Env struct {
+	Config    *config.Config // nil if loading failed
+	ConfigErr error
+	NetrcPath string
+	Notion    SchemaReader
+	CachePath string
+}
+
+// expectation is a property the app needs and the type it must have. An
+// empty type accepts any. Optional properties may be missing or unnamed.
+type expectation struct {
+	key      string
+	name     string
+	typ      string
+	optional bool
+}
+
+// Run performs all checks. Notion checks are skipped when the configuration
+// they need is missing.
+func Run(ctx context.Context, env Env) Report {
+	var r Report
+
+	configOK := r.add(checkConfig(env))
+	credentialsOK := r.add(checkCredentials(env))
+	mappingOK := r.add(checkMapping(env))
+
+	if configOK && credentialsOK && mappingOK && env.Notion != nil {
+		props := env.Config.Properties
+		r.add(checkDatabase(ctx, env.Notion, "Reading database", env.Config.NotionDatabaseID, []expectation{
+			{"title", props.Title, "title", false},
+			{"url", props.URL, "url", false},
+			{"tags", props.Tags, "multi_select", false},
+			{"done", props.Done, "checkbox", false},
+			{"notes", props.Notes, "rich_text", true},
+			{"reading_time", props.ReadingTime, "number", true},
+			{"priority", props.Priority, "number", true},
+		}))
+		r.add(checkDatabase(ctx, env.Notion, "Weeks database", env.Config.NotionWeeksDBID, []expectation{
+			{"week_name", props.WeekName, "", false},
+			{"week_span", props.WeekSpan, "date", false},
+			{"week_reading_list", props.WeekReadingList, "relation", false},
+		}))
+	} else {
+		reason := "needs a valid configuration and API key"
+		r.add(Check{Name: "Reading database", Status: Skip, Detail: reason})
+		r.add(Check{Name: "Weeks database", Status: Skip, Detail: reason})
+	}
+
+	r.add(checkCache(ctx, env.CachePath))
+	return r
+}
+
+// add appends the check and reports whether it passed or only warned.
+func (r *Report) add(c Check) bool {
+	r.Checks = append(r.Checks, c)
+	return c.Status == Pass || c.Status == Warn
+}
+
+func checkConfig(env Env) Check {
+	c := Check{Name: "Configuration"}
+	switch {
+	case env.ConfigErr != nil:
+		c.Status, c.Detail = Fail, env.ConfigErr.Error()
+		c.Hint = fmt.Sprintf("Fix the syntax of %s or run 'readings setup'", config.ConfigFileName)
+	case env.Config.NotionDatabaseID == "":
+		c.Status, c.Detail = Fail, "notion_database_id is not set"
+		c.Hint = "Run 'readings setup'"
+	case env.Config.NotionWeeksDBID == "":
+		c.Status, c.Detail = Fail, "notion_weeks_db_id is not set"
+		c.Hint = "Run 'readings setup'"
+	default:
+		c.Status, c.Detail = Pass, fmt.Sprintf("database IDs found in %s", config.ConfigFileName)
+	}
+	return c
+}
+
+func checkCredentials(env Env) Check {
+	c := Check{Name: "Credentials"}
+	if env.Config == nil {
+		c.Status, c.Detail = Skip, "needs a readable configuration"
+		return c
+	}
+
+	if env.Config.NotionAPIKey == "" {
+		c.Status = Fail
+		c.Detail = fmt.Sprintf("no API key for machine %s in %s", config.NetrcMachineName, env.NetrcPath)
+		c.Hint = "Run 'readings setup' or add 'machine notion.so login apikey password <secret>' to ~/.netrc"
+		return c
+	}
+
+	c.Status, c.Detail = Pass, fmt.Sprintf("API key found in %s", env.NetrcPath)
+	if info, err := os.Stat(env.NetrcPath); err == nil && info.Mode().Perm()&0077 != 0 {
+		c.Status = Warn
+		c.Detail += fmt.Sprintf(" but it is readable by others (%04o)", info.Mode().Perm())
+		c.Hint = fmt.Sprintf("chmod 600 %s", env.NetrcPath)
+	}
+	return c
+}
+
+func checkMapping(env Env) Check {
+	c := Check{Name: "Property mapping"}
+	if env.Config == nil {
+		c.Status, c.Detail = Skip, "needs a readable configuration"
+		return c
+	}
+
+	if err := env.Config.Properties.Validate(); err != nil {
+		c.Status, c.Detail = Fail, err.Error()
+		c.Hint = fmt.Sprintf("Fix the [notion.properties] section of %s", config.ConfigFileName)
+		return c
+	}
+
+	c.Status, c.Detail = Pass, "all properties are named"
+	return c
+}
+
+func checkDatabase(ctx context.Context, notion SchemaReader, name, databaseID string, expected []expectation) Check {
+	c := Check{Name: name}
+
+	types, err := notion.PropertyTypes(ctx, databaseID)
+	if err != nil {
+		c.Status, c.Detail, c.Hint = Fail, err.Error(), connectionHint(err)
+		return c
+	}
+
+	var problems []string
+	checked := 0
+	for _, e := range expected {
+		typ, ok := types[e.name]
+		if e.optional && (e.name == "" || !ok) {
+			continue
+		}
+		checked++
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("property %q (%s) is missing", e.name, e.key))
+		case e.typ != "" && typ != e.typ:
+			problems = append(problems, fmt.Sprintf("property %q (%s) is %s, expected %s", e.name, e.key, typ, e.typ))
+		}
+	}
+
+	if len(problems) > 0 {
+		c.Status, c.Detail = Fail, strings.Join(problems, "; ")
+		c.Hint = fmt.Sprintf("Rename the Notion columns or map them in the [notion.properties] section of %s", config.ConfigFileName)
+		return c
+	}
+
+	c.Status, c.Detail = Pass, fmt.Sprintf("%d properties have the expected types", checked)
+	return c
+}
+
+func connectionHint(err error) string {
+	var apiErr *notionapi.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Status {
+		case http.StatusUnauthorized:
+			return "The API key was rejected; create a new integration secret and run 'readings setup'"
+		case http.StatusNotFound:
+			return "Share the database with your integration (••• → Connections) and check the ID"
+		case http.StatusBadRequest:
+			return "Check that the configured ID is a database, not a page"
+		}
+	}
+	return "Check your network connection and try again"
+}
+
+func checkCache(ctx context.Context, path string) Check {
+	c := Check{Name: "Local cache"}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		c.Status, c.Detail = Warn, fmt.Sprintf("%s does not exist yet", path)
+		c.Hint = "It is created on the first 'readings' or 'readings sync' run"
+		return c
+	}
+
+	// Only read the version: opening the store would run migrations, and
+	// some of them reset the sync state.
+	version, err := storage.FileSchemaVersion(ctx, path)
+	if err != nil {
+		c.Status, c.Detail = Fail, err.Error()
+		c.Hint = fmt.Sprintf("Move %s aside and run 'readings sync --full' to rebuild it", path)
+		return c
+	}
+
+	latest := storage.LatestSchemaVersion()
+	switch {
+	case version > latest:
+		c.Status, c.Detail = Fail, fmt.Sprintf("%s is at schema version %d, newer than the supported %d", path, version, latest)
+		c.Hint = "Upgrade readings to the latest release"
+	case version < latest:
+		c.Status, c.Detail = Warn, fmt.Sprintf("%s needs migration from schema version %d to %d", path, version, latest)
+		c.Hint = "It is migrated on the next 'readings' or 'readings sync' run"
+	default:
+		c.Status, c.Detail = Pass, fmt.Sprintf("%s at schema version %d", path, version)
+	}
+	return c
+}
@@ -0,0 +1,186 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jomei/notionapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/config"
+	"productivity.go/internal/storage"
+)
+
+type fakeSchemas map[string]map[string]string
+
+func (f fakeSchemas) PropertyTypes(ctx context.Context, databaseID string) (map[string]string, error) {
+	types, ok := f[databaseID]
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve database %s: %w", databaseID,
+			&notionapi.Error{Status: http.StatusNotFound, Message: "Could not find database"})
+	}
+	return types, nil
+}
+
+func validSchemas() fakeSchemas {
+	return fakeSchemas{
+		"reading-db": {"Name": "title", "URL": "url", "Tags": "multi_select", "Done": "checkbox"},
+		"weeks-db":   {"Name": "title", "🗓️ Span": "date", "📑 Reading List": "relation"},
+	}
+}
+
+func validEnv(t *testing.T) Env {
+	t.Helper()
+	dir := t.TempDir()
+
+	netrcPath := filepath.Join(dir, ".netrc")
+	require.NoError(t, os.WriteFile(netrcPath, []byte("machine notion.so login apikey password secret\n"), 0600))
+
+	cachePath := filepath.Join(dir, storage.DBFileName)
+	store, err := storage.Open(cachePath)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	return Env{
+		Config: &config.Config{
+			NotionAPIKey:     "secret",
+			NotionDatabaseID: "reading-db",
+			NotionWeeksDBID:  "weeks-db",
+			Properties:       config.DefaultProperties(),
+		},
+		NetrcPath: netrcPath,
+		Notion:    validSchemas(),
+		CachePath: cachePath,
+	}
+}
+
+func statuses(r Report) map[string]Status {
+	result := make(map[string]Status)
+	for _, c := range r.Checks {
+		result[c.Name] = c.Status
+	}
+	return result
+}
+
+func TestRun_AllPass(t *testing.T) {
+	report := Run(context.Background(), validEnv(t))
+	assert.True(t, report.OK())
+	for _, c := range report.Checks {
+		assert.Equal(t, Pass, c.Status, c.Name)
+	}
+}
+
+func TestRun_WrongPropertyType(t *testing.T) {
+	env := validEnv(t)
+	schemas := validSchemas()
+	schemas["reading-db"]["Tags"] = "rich_text"
+	delete(schemas["weeks-db"], "🗓️ Span")
+	env.Notion = schemas
+
+	report := Run(context.Background(), env)
+	assert.False(t, report.OK())
+
+	var out bytes.Buffer
+	report.Write(&out)
+	assert.Contains(t, out.String(), `property "Tags" (tags) is rich_text, expected multi_select`)
+	assert.Contains(t, out.String(), `property "🗓️ Span" (week_span) is missing`)
+	assert.Contains(t, out.String(), "[notion.properties]")
+}
+
+func TestRun_OptionalProperties(t *testing.T) {
+	env := validEnv(t)
+
+	// Missing optional properties are fine.
+	report := Run(context.Background(), env)
+	assert.Equal(t, Pass, statuses(report)["Reading database"])
+
+	// Present ones must have the right type.
+	schemas := validSchemas()
+	schemas["reading-db"]["Reading Time"] = "rich_text"
+	env.Notion = schemas
+
+	report = Run(context.Background(), env)
+	assert.Equal(t, Fail, statuses(report)["Reading database"])
+
+	var out bytes.Buffer
+	report.Write(&out)
+	assert.Contains(t, out.String(), `property "Reading Time" (reading_time) is rich_text, expected number`)
+}
+
+func TestRun_DatabaseNotShared(t *testing.T) {
+	env := validEnv(t)
+	env.Config.NotionWeeksDBID = "unshared-db"
+
+	report := Run(context.Background(), env)
+	assert.False(t, report.OK())
+	assert.Equal(t, Fail, statuses(report)["Weeks database"])
+	assert.Equal(t, Pass, statuses(report)["Reading database"])
+
+	var out bytes.Buffer
+	report.Write(&out)
+	assert.Contains(t, out.String(), "Share the database with your integration")
+}
+
+func TestRun_MissingAPIKeySkipsNotion(t *testing.T) {
+	env := validEnv(t)
+	env.Config.NotionAPIKey = ""
+	env.Notion = nil
+
+	report := Run(context.Background(), env)
+	got := statuses(report)
+	assert.Equal(t, Fail, got["Credentials"])
+	assert.Equal(t, Skip, got["Reading database"])
+	assert.Equal(t, Skip, got["Weeks database"])
+	assert.Equal(t, Pass, got["Local cache"])
+}
+
+func TestRun_WorldReadableNetrcWarns(t *testing.T) {
+	env := validEnv(t)
+	require.NoError(t, os.Chmod(env.NetrcPath, 0644))
+
+	report := Run(context.Background(), env)
+	assert.True(t, report.OK())
+	assert.Equal(t, Warn, statuses(report)["Credentials"])
+}
+
+func TestRun_CacheTooNew(t *testing.T) {
+	env := validEnv(t)
+
+	// Simulate a database written by a newer binary.
+	db, err := sql.Open("sqlite", env.CachePath)
+	require.NoError(t, err)
+	_, err = db.Exec(fmt.Sprintf("PRAGMA user_version = %d", storage.LatestSchemaVersion()+1))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	report := Run(context.Background(), env)
+	assert.Equal(t, Fail, statuses(report)["Local cache"])
+
+	var out bytes.Buffer
+	report.Write(&out)
+	assert.Contains(t, out.String(), "Upgrade readings")
+}
+
+func TestRun_CacheNeedsMigration(t *testing.T) {
+	env := validEnv(t)
+
+	db, err := sql.Open("sqlite", env.CachePath)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("PRAGMA user_version = 1")
+	require.NoError(t, err)
+
+	report := Run(context.Background(), env)
+	assert.Equal(t, Warn, statuses(report)["Local cache"])
+
+	// Doctor does not migrate the cache itself.
+	var version int
+	require.NoError(t, db.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, 1, version)
+}
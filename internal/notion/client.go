@@ -1,40 +1,187 @@
 package notion
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
 	"time"
 
 	"github.com/jomei/notionapi"
+	"productivity.go/internal/config"
 	"productivity.go/internal/readings"
 )
 
 type Client struct {
 	api        *notionapi.Client
+	http       *http.Client // Shared with api, for requests made without it
+	token      string
 	databaseID notionapi.DatabaseID
 	weeksDBID  notionapi.DatabaseID
+	props      config.NotionProperties
+	retry      RetryPolicy
+	transport  http.RoundTripper
+	baseURL    *url.URL
+	baseURLErr error          // Set by WithBaseURL; fails every request
+	location   *time.Location // Zone of all-day week spans
 }
 
-func NewClient(apiKey, databaseID, weeksDBID string) *Client {
-	return &Client{
-		api:        notionapi.NewClient(notionapi.Token(apiKey)),
+// Option configures a Client.
+type Option func(*Client)
+
+// WithProperties overrides the default Notion property names.
+func WithProperties(props config.NotionProperties) Option {
+	return func(c *Client) {
+		c.props = props
+	}
+}
+
+// WithRetryPolicy overrides DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithTransport sends requests through base instead of
+// http.DefaultTransport. Requests are still spaced and retried.
+func WithTransport(base http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = base
+	}
+}
+
+// WithBaseURL sends requests to another server than https://api.notion.com,
+// such as a notiontest.Server. The API version path is appended to it.
+// If baseURL is not an absolute URL every request fails, rather than going
+// to the real API with the token meant for the other server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			c.baseURL, c.baseURLErr = nil, fmt.Errorf("invalid Notion base URL %q", baseURL)
+			return
+		}
+		c.baseURL, c.baseURLErr = u, nil
+	}
+}
+
+// WithLocation reads all-day week spans as days in loc instead of
+// time.Local.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		c.location = loc
+	}
+}
+
+func NewClient(apiKey, databaseID, weeksDBID string, opts ...Option) *Client {
+	c := &Client{
 		databaseID: notionapi.DatabaseID(databaseID),
 		weeksDBID:  notionapi.DatabaseID(weeksDBID),
+		props:      config.DefaultProperties(),
+		retry:      DefaultRetryPolicy(),
+		location:   time.Local,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	// notionapi's own retry resends 429s with an already consumed body,
+	// so it is turned off in favor of retryTransport.
+	transport := c.transport
+	if c.baseURL != nil {
+		transport = rebase{base: c.baseURL, next: transport}
+	}
+
+	var rt http.RoundTripper = newRetryTransport(transport, c.retry)
+	if c.baseURLErr != nil {
+		rt = failTransport{err: c.baseURLErr}
+	}
+
+	c.http = &http.Client{Transport: rt}
+	c.token = apiKey
+	c.api = notionapi.NewClient(notionapi.Token(apiKey),
+		notionapi.WithHTTPClient(c.http),
+		notionapi.WithRetry(1),
+	)
+	return c
 }
 
-func (c *Client) FetchArticles(ctx context.Context) ([]readings.Article, error) {
+// DatabaseID returns the ID of the reading database, used to key sync state.
+func (c *Client) DatabaseID() string {
+	return c.databaseID.String()
+}
+
+// FetchArticles returns the articles that are not done. A non-zero since
+// limits the query to pages edited at or after that time and includes pages
+// marked done, so the caller can drop them from its cache.
+func (c *Client) FetchArticles(ctx context.Context, since time.Time) ([]readings.Article, error) {
 	var articles []readings.Article
-	var cursor notionapi.Cursor
 
+	var filter notionapi.Filter = &notionapi.PropertyFilter{
+		Property: c.props.Done,
+		Checkbox: &notionapi.CheckboxFilterCondition{
+			DoesNotEqual: true,
+		},
+	}
+	if !since.IsZero() {
+		// Notion rounds last_edited_time down to the minute.
+		editedAfter := notionapi.Date(since.Truncate(time.Minute))
+		filter = &notionapi.TimestampFilter{
+			Timestamp:      notionapi.TimestampLastEdited,
+			LastEditedTime: &notionapi.DateFilterCondition{OnOrAfter: &editedAfter},
+		}
+	}
+
+	pages, err := c.queryArticles(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, page := range pages {
+		article, err := c.parsePage(page)
+		if err != nil {
+			// Log error but continue? For now, let's skip malformed pages
+			continue
+		}
+		articles = append(articles, article)
+	}
+
+	return articles, nil
+}
+
+// DoneArticleIDs returns the IDs of the pages in the reading database that
+// are marked Done. Pages in the trash are left out.
+func (c *Client) DoneArticleIDs(ctx context.Context) ([]string, error) {
+	pages, err := c.queryArticles(ctx, &notionapi.PropertyFilter{
+		Property: c.props.Done,
+		Checkbox: &notionapi.CheckboxFilterCondition{Equals: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(pages))
+	for i, page := range pages {
+		ids[i] = page.ID.String()
+	}
+	return ids, nil
+}
+
+// queryArticles returns every page of the reading database matching filter,
+// following the result cursors.
+func (c *Client) queryArticles(ctx context.Context, filter notionapi.Filter) ([]notionapi.Page, error) {
+	var pages []notionapi.Page
+	var cursor notionapi.Cursor
 	for {
 		req := &notionapi.DatabaseQueryRequest{
-			Filter: &notionapi.PropertyFilter{
-				Property: "Done",
-				Checkbox: &notionapi.CheckboxFilterCondition{
-					DoesNotEqual: true,
-				},
-			},
+			Filter:      filter,
 			StartCursor: cursor,
 		}
 
@@ -42,102 +189,194 @@ func (c *Client) FetchArticles(ctx context.Context) ([]readings.Article, error)
 		if err != nil {
 			return nil, fmt.Errorf("failed to query notion database: %w", err)
 		}
-
-		for _, page := range resp.Results {
-			article, err := parsePage(page)
-			if err != nil {
-				// Log error but continue? For now, let's skip malformed pages
-				continue
-			}
-			articles = append(articles, article)
-		}
+		pages = append(pages, resp.Results...)
 
 		if !resp.HasMore {
-			break
+			return pages, nil
 		}
 		cursor = resp.NextCursor
 	}
-
-	return articles, nil
 }
 
-func parsePage(page notionapi.Page) (readings.Article, error) {
+func (c *Client) parsePage(page notionapi.Page) (readings.Article, error) {
 	var title string
-	if prop, ok := page.Properties["Name"].(*notionapi.TitleProperty); ok {
+	if prop, ok := page.Properties[c.props.Title].(*notionapi.TitleProperty); ok {
 		for _, t := range prop.Title {
 			title += t.PlainText
 		}
 	}
 
 	var url string
-	if prop, ok := page.Properties["URL"].(*notionapi.URLProperty); ok {
+	if prop, ok := page.Properties[c.props.URL].(*notionapi.URLProperty); ok {
 		url = prop.URL
 	}
 
 	var tags []string
-	if prop, ok := page.Properties["Tags"].(*notionapi.MultiSelectProperty); ok {
+	if prop, ok := page.Properties[c.props.Tags].(*notionapi.MultiSelectProperty); ok {
 		for _, option := range prop.MultiSelect {
 			tags = append(tags, option.Name)
 		}
 	}
 
+	var done bool
+	if prop, ok := page.Properties[c.props.Done].(*notionapi.CheckboxProperty); ok {
+		done = prop.Checkbox
+	}
+
+	var notes string
+	if prop, ok := page.Properties[c.props.Notes].(*notionapi.RichTextProperty); ok {
+		for _, t := range prop.RichText {
+			notes += t.PlainText
+		}
+	}
+
+	var minutes int
+	if prop, ok := page.Properties[c.props.ReadingTime].(*notionapi.NumberProperty); ok {
+		minutes = int(math.Round(prop.Number))
+	}
+
+	var priority int
+	if prop, ok := page.Properties[c.props.Priority].(*notionapi.NumberProperty); ok {
+		priority = int(math.Round(prop.Number))
+	}
+
 	// If URL is empty, maybe use the page URL?
 	if url == "" {
 		url = page.URL
 	}
 
 	return readings.Article{
-		ID:        page.ID.String(),
-		Title:     title,
-		URL:       url,
-		Tags:      tags,
-		FetchedAt: time.Now(),
+		ID:             page.ID.String(),
+		Title:          title,
+		URL:            url,
+		Tags:           tags,
+		FetchedAt:      time.Now(),
+		Done:           done,
+		CreatedAt:      page.CreatedTime,
+		Notes:          notes,
+		ReadingMinutes: minutes,
+		Priority:       priority,
 	}, nil
 }
 
-func (c *Client) FetchCurrentWeek(ctx context.Context) (*readings.Week, error) {
-	now := time.Now()
-	// We sort by Name descending to get recent weeks first (assuming naming convention follows date).
+// FetchWeekAt returns the week whose span contains t.
+func (c *Client) FetchWeekAt(ctx context.Context, t time.Time) (*readings.Week, error) {
+	// Notion compares a span by its start date, and all-day spans start at
+	// midnight in whatever zone Notion picks, so the filter allows a day of
+	// slack and Contains decides.
+	startsBy := notionapi.Date(t.Add(24 * time.Hour))
 	req := &notionapi.DatabaseQueryRequest{
+		Filter: &notionapi.PropertyFilter{
+			Property: c.props.WeekSpan,
+			Date:     &notionapi.DateFilterCondition{OnOrBefore: &startsBy},
+		},
 		Sorts: []notionapi.SortObject{
 			{
-				Property:  "Name",
+				Property:  c.props.WeekSpan,
 				Direction: notionapi.SortOrderDESC,
 			},
 		},
-		PageSize: 20,
+		PageSize: 10,
 	}
 
-	resp, err := c.api.Database.Query(ctx, c.weeksDBID, req)
+	resp, err := c.queryWeeks(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query weeks database: %w", err)
 	}
 
 	for _, page := range resp.Results {
-		if prop, ok := page.Properties["🗓️ Span"].(*notionapi.DateProperty); ok {
-			if prop.Date.Start != nil {
-				start := time.Time(*prop.Date.Start)
-				var end time.Time
-				if prop.Date.End != nil {
-					end = time.Time(*prop.Date.End)
-				} else {
-					// If no end date, assume it covers the start day
-					end = start.Add(24 * time.Hour)
-				}
-
-				// Adjust end to be end of the day if it's 00:00:00
-				if end.Hour() == 0 && end.Minute() == 0 && end.Second() == 0 {
-					end = end.Add(24 * time.Hour).Add(-1 * time.Second)
-				}
-
-				if !now.Before(start) && !now.After(end) {
-					return parseWeek(page)
-				}
+		week, ok := c.parseWeek(page)
+		if ok && week.Contains(t) {
+			return week, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no week found in Notion (looked for a %q date containing %s)", c.props.WeekSpan, t.Format("2006-01-02"))
+}
+
+// maxWeekSpan bounds how long before from a week overlapping it may start.
+const maxWeekSpan = 31 * 24 * time.Hour
+
+// ListWeeks returns the weeks overlapping from through to, earliest first.
+func (c *Client) ListWeeks(ctx context.Context, from, to time.Time) ([]readings.Week, error) {
+	startsAfter := notionapi.Date(from.Add(-maxWeekSpan))
+	startsBy := notionapi.Date(to.Add(24 * time.Hour))
+	filter := notionapi.AndCompoundFilter{
+		notionapi.PropertyFilter{
+			Property: c.props.WeekSpan,
+			Date:     &notionapi.DateFilterCondition{OnOrAfter: &startsAfter},
+		},
+		notionapi.PropertyFilter{
+			Property: c.props.WeekSpan,
+			Date:     &notionapi.DateFilterCondition{OnOrBefore: &startsBy},
+		},
+	}
+
+	var weeks []readings.Week
+	var cursor notionapi.Cursor
+	for {
+		req := &notionapi.DatabaseQueryRequest{
+			Filter: filter,
+			Sorts: []notionapi.SortObject{
+				{
+					Property:  c.props.WeekSpan,
+					Direction: notionapi.SortOrderASC,
+				},
+			},
+			StartCursor: cursor,
+		}
+
+		resp, err := c.queryWeeks(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query weeks database: %w", err)
+		}
+
+		for _, page := range resp.Results {
+			week, ok := c.parseWeek(page)
+			if ok && !week.End.Before(from) && !week.Start.After(to) {
+				weeks = append(weeks, *week)
 			}
 		}
+
+		if !resp.HasMore {
+			break
+		}
+		cursor = resp.NextCursor
+	}
+
+	// Spans sort by their start as Notion stores it; all-day spans are
+	// moved to local time by parseWeek.
+	sort.SliceStable(weeks, func(i, j int) bool { return weeks[i].Start.Before(weeks[j].Start) })
+	return weeks, nil
+}
+
+// FetchWeek returns the week page with the given ID.
+func (c *Client) FetchWeek(ctx context.Context, weekPageID string) (*readings.Week, error) {
+	var page weekPage
+	if err := c.do(ctx, http.MethodGet, "pages/"+url.PathEscape(weekPageID), nil, &page); err != nil {
+		return nil, fmt.Errorf("failed to retrieve week: %w", rejected(err))
 	}
 
-	return nil, fmt.Errorf("no current week found in Notion")
+	week, ok := c.parseWeek(page)
+	if !ok {
+		return nil, fmt.Errorf("%w: week %s has no %q date", readings.ErrRejected, weekPageID, c.props.WeekSpan)
+	}
+	return week, nil
+}
+
+// PropertyTypes returns the type of each property of a database, keyed by
+// property name.
+func (c *Client) PropertyTypes(ctx context.Context, databaseID string) (map[string]string, error) {
+	db, err := c.api.Database.Get(ctx, notionapi.DatabaseID(databaseID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve database %s: %w", databaseID, err)
+	}
+
+	types := make(map[string]string, len(db.Properties))
+	for name, prop := range db.Properties {
+		types[name] = string(prop.GetType())
+	}
+	return types, nil
 }
 
 func getKeys(m map[string]notionapi.Property) []string {
@@ -148,6 +387,36 @@ func getKeys(m map[string]notionapi.Property) []string {
 	return keys
 }
 
+// CreateArticle adds a page for article to the reading database and returns
+// it as Notion stored it. Only the title, URL and tags are set.
+func (c *Client) CreateArticle(ctx context.Context, article readings.Article) (readings.Article, error) {
+	props := notionapi.Properties{
+		c.props.Title: notionapi.TitleProperty{
+			Title: []notionapi.RichText{{Text: &notionapi.Text{Content: article.Title}}},
+		},
+		c.props.URL: notionapi.URLProperty{URL: article.URL},
+	}
+	if len(article.Tags) > 0 {
+		options := make([]notionapi.Option, len(article.Tags))
+		for i, tag := range article.Tags {
+			options[i] = notionapi.Option{Name: tag}
+		}
+		props[c.props.Tags] = notionapi.MultiSelectProperty{MultiSelect: options}
+	}
+
+	page, err := c.api.Page.Create(ctx, &notionapi.PageCreateRequest{
+		Parent: notionapi.Parent{
+			Type:       notionapi.ParentTypeDatabaseID,
+			DatabaseID: c.databaseID,
+		},
+		Properties: props,
+	})
+	if err != nil {
+		return readings.Article{}, fmt.Errorf("failed to create article: %w", rejected(err))
+	}
+	return c.parsePage(*page)
+}
+
 func (c *Client) UpdateWeekReadingList(ctx context.Context, weekPageID string, readingPageIDs []string) error {
 	relations := make([]notionapi.Relation, len(readingPageIDs))
 	for i, id := range readingPageIDs {
@@ -156,7 +425,7 @@ func (c *Client) UpdateWeekReadingList(ctx context.Context, weekPageID string, r
 
 	params := &notionapi.PageUpdateRequest{
 		Properties: notionapi.Properties{
-			"📑 Reading List": notionapi.RelationProperty{
+			c.props.WeekReadingList: notionapi.RelationProperty{
 				Relation: relations,
 			},
 		},
@@ -164,21 +433,192 @@ func (c *Client) UpdateWeekReadingList(ctx context.Context, weekPageID string, r
 
 	_, err := c.api.Page.Update(ctx, notionapi.PageID(weekPageID), params)
 	if err != nil {
-		return fmt.Errorf("failed to update week reading list: %w", err)
+		return fmt.Errorf("failed to update week reading list: %w", rejected(err))
+	}
+	return nil
+}
+
+func (c *Client) MarkDone(ctx context.Context, articleID string) error {
+	params := &notionapi.PageUpdateRequest{
+		Properties: notionapi.Properties{
+			c.props.Done: notionapi.CheckboxProperty{
+				Checkbox: true,
+			},
+		},
+	}
+
+	_, err := c.api.Page.Update(ctx, notionapi.PageID(articleID), params)
+	if err != nil {
+		return fmt.Errorf("failed to mark article as done: %w", rejected(err))
 	}
 	return nil
 }
 
-func parseWeek(page notionapi.Page) (*readings.Week, error) {
+func (c *Client) SetTags(ctx context.Context, articleID string, tags []string) error {
+	options := make([]notionapi.Option, len(tags))
+	for i, tag := range tags {
+		options[i] = notionapi.Option{Name: tag}
+	}
+
+	params := &notionapi.PageUpdateRequest{
+		Properties: notionapi.Properties{
+			c.props.Tags: notionapi.MultiSelectProperty{
+				MultiSelect: options,
+			},
+		},
+	}
+
+	_, err := c.api.Page.Update(ctx, notionapi.PageID(articleID), params)
+	if err != nil {
+		return fmt.Errorf("failed to update tags: %w", rejected(err))
+	}
+	return nil
+}
+
+// weekPage is a week page as Notion sends it. Week pages are decoded here
+// rather than by notionapi, which reads a date without a time as UTC
+// midnight and so cannot tell it from a time at UTC midnight.
+type weekPage struct {
+	ID         string                     `json:"id"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+type weekQueryResponse struct {
+	Results    []weekPage       `json:"results"`
+	HasMore    bool             `json:"has_more"`
+	NextCursor notionapi.Cursor `json:"next_cursor"`
+}
+
+func (c *Client) queryWeeks(ctx context.Context, req *notionapi.DatabaseQueryRequest) (*weekQueryResponse, error) {
+	var resp weekQueryResponse
+	if err := c.do(ctx, http.MethodPost, "databases/"+c.weeksDBID.String()+"/query", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// notionVersion is the API version notionapi sends.
+const notionVersion = "2022-06-28"
+
+// do sends a request to the Notion API the way notionapi does and decodes
+// the response into out. Errors from Notion are returned as
+// *notionapi.Error.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, "https://api.notion.com/v1/"+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Notion-Version", notionVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &notionapi.Error{}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		apiErr.Status = resp.StatusCode
+		return apiErr
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// parseWeek reads a week page. It reports false if the page has no span.
+func (c *Client) parseWeek(page weekPage) (*readings.Week, bool) {
+	var span struct {
+		Date *struct {
+			Start string  `json:"start"`
+			End   *string `json:"end"`
+		} `json:"date"`
+	}
+	if err := json.Unmarshal(page.Properties[c.props.WeekSpan], &span); err != nil || span.Date == nil {
+		return nil, false
+	}
+
+	start, startAllDay, err := c.spanTime(span.Date.Start)
+	if err != nil {
+		return nil, false
+	}
+	var end time.Time
+	switch {
+	case span.Date.End != nil:
+		var endAllDay bool
+		end, endAllDay, err = c.spanTime(*span.Date.End)
+		if err != nil {
+			return nil, false
+		}
+		if endAllDay {
+			end = endOfDay(end)
+		}
+	case startAllDay:
+		end = endOfDay(start)
+	default:
+		end = start.Add(24 * time.Hour)
+	}
+
+	var readingList struct {
+		Relation []struct {
+			ID string `json:"id"`
+		} `json:"relation"`
+	}
 	var readingListIDs []string
-	if prop, ok := page.Properties["📑 Reading List"].(*notionapi.RelationProperty); ok {
-		for _, rel := range prop.Relation {
-			readingListIDs = append(readingListIDs, rel.ID.String())
+	if json.Unmarshal(page.Properties[c.props.WeekReadingList], &readingList) == nil {
+		for _, rel := range readingList.Relation {
+			readingListIDs = append(readingListIDs, rel.ID)
 		}
 	}
 
 	return &readings.Week{
-		ID:             page.ID.String(),
+		ID:             page.ID,
+		Start:          start,
+		End:            end,
 		ReadingListIDs: readingListIDs,
-	}, nil
+	}, true
+}
+
+// spanTime parses a date from a span and reports whether it is a date
+// without a time. Those mean the calendar day wherever the user is, so they
+// are read as midnight in the client's location.
+func (c *Client) spanTime(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, c.location); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// endOfDay returns the last second of the local day starting at midnight.
+func endOfDay(midnight time.Time) time.Time {
+	return midnight.AddDate(0, 0, 1).Add(-time.Second)
+}
+
+// rejected marks errors Notion will return again for the same request, so
+// queued changes causing them are not retried. Rate limits, conflicts,
+// authentication errors and pages that are not found or not shared, which
+// can come back when the integration is reconnected, are left as they are.
+func rejected(err error) error {
+	var apiErr *notionapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Status < 400 || apiErr.Status >= 500 {
+		return err
+	}
+	switch apiErr.Status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+		http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return err
+	}
+	return fmt.Errorf("%w: %w", readings.ErrRejected, err)
 }
@@ -0,0 +1,346 @@
+package notion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/config"
+	"productivity.go/internal/notion/notiontest"
+	"productivity.go/internal/readings"
+)
+
+const (
+	readingDB = "a0e3e448792a4aa59f0d4576333457e9"
+	weeksDB   = "f291b0e4b2f64b7d818fe996318ecdf1"
+)
+
+// fakeNotion starts a notiontest.Server with the default template's
+// databases and a client pointed at it.
+func fakeNotion(t *testing.T, opts ...Option) (*notiontest.Server, *Client) {
+	t.Helper()
+	srv := notiontest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Token = "secret"
+
+	srv.AddDatabase(readingDB, map[string]string{
+		"Name":         "title",
+		"URL":          "url",
+		"Tags":         "multi_select",
+		"Done":         "checkbox",
+		"Notes":        "rich_text",
+		"Reading Time": "number",
+		"Priority":     "number",
+	})
+	srv.AddDatabase(weeksDB, map[string]string{
+		"Name":           "title",
+		"🗓️ Span":        "date",
+		"📑 Reading List": "relation",
+	})
+
+	opts = append([]Option{WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{})}, opts...)
+	client := NewClient("secret", readingDB, weeksDB, opts...)
+	return srv, client
+}
+
+func addArticle(srv *notiontest.Server, title string, done bool, tags ...string) string {
+	return srv.AddPage(readingDB, notiontest.Properties{
+		"Name": notiontest.Title(title),
+		"URL":  notiontest.URL("https://example.com/" + title),
+		"Tags": notiontest.MultiSelect(tags...),
+		"Done": notiontest.Checkbox(done),
+	})
+}
+
+func countRequests(srv *notiontest.Server, request string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r == request {
+			n++
+		}
+	}
+	return n
+}
+
+func TestClient_FetchArticlesFollowsCursors(t *testing.T) {
+	srv, client := fakeNotion(t)
+	srv.PageSize = 2
+
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	srv.Now = func() time.Time { return created }
+	id := srv.AddPage(readingDB, notiontest.Properties{
+		"Name":         notiontest.Title("Go Generics"),
+		"URL":          notiontest.URL("https://go.dev/blog/intro-generics"),
+		"Tags":         notiontest.MultiSelect("go", "programming"),
+		"Done":         notiontest.Checkbox(false),
+		"Notes":        notiontest.RichText("Read before the workshop"),
+		"Reading Time": notiontest.Number(11.6),
+		"Priority":     notiontest.Number(2),
+	})
+	addArticle(srv, "two", false)
+	addArticle(srv, "finished", true)
+	addArticle(srv, "three", false)
+	addArticle(srv, "four", false)
+
+	articles, err := client.FetchArticles(context.Background(), time.Time{})
+	require.NoError(t, err)
+
+	require.Len(t, articles, 4)
+	assert.Equal(t, 2, countRequests(srv, "POST /v1/databases/"+readingDB+"/query"))
+
+	first := articles[0]
+	assert.Equal(t, id, first.ID)
+	assert.Equal(t, "Go Generics", first.Title)
+	assert.Equal(t, "https://go.dev/blog/intro-generics", first.URL)
+	assert.Equal(t, []string{"go", "programming"}, first.Tags)
+	assert.Equal(t, "Read before the workshop", first.Notes)
+	assert.Equal(t, 12, first.ReadingMinutes)
+	assert.Equal(t, 2, first.Priority)
+	assert.True(t, created.Equal(first.CreatedAt))
+	assert.False(t, first.Done)
+
+	for _, a := range articles {
+		assert.NotEqual(t, "finished", a.Title)
+	}
+}
+
+func TestClient_FetchArticlesSince(t *testing.T) {
+	srv, client := fakeNotion(t)
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	srv.Now = func() time.Time { return now }
+	old := addArticle(srv, "old", false)
+	edited := addArticle(srv, "edited", false)
+
+	now = now.Add(time.Hour)
+	srv.SetProperty(edited, "Done", notiontest.Checkbox(true))
+	added := addArticle(srv, "added", false)
+
+	articles, err := client.FetchArticles(context.Background(), now.Add(-30*time.Minute))
+	require.NoError(t, err)
+
+	byID := make(map[string]readings.Article)
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+	assert.Len(t, byID, 2)
+	assert.NotContains(t, byID, old)
+	assert.True(t, byID[edited].Done, "Pages marked done are returned so the cache can drop them")
+	assert.False(t, byID[added].Done)
+}
+
+// addWeek adds a week page spanning the seven days from start.
+func addWeek(srv *notiontest.Server, start time.Time, readingList ...string) string {
+	end := start.AddDate(0, 0, 6)
+	return srv.AddPage(weeksDB, notiontest.Properties{
+		"Name":           notiontest.Title(start.Format("2006-01-02")),
+		"🗓️ Span":        notiontest.Date(start.Format("2006-01-02"), end.Format("2006-01-02")),
+		"📑 Reading List": notiontest.Relation(readingList...),
+	})
+}
+
+func TestClient_FetchWeekAt(t *testing.T) {
+	// West of UTC, Sunday evening is already Monday in UTC.
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	srv, client := fakeNotion(t, WithLocation(loc))
+
+	article := addArticle(srv, "planned", false)
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, loc)
+	addWeek(srv, monday.AddDate(0, 0, -7))
+	current := addWeek(srv, monday, article)
+	next := addWeek(srv, monday.AddDate(0, 0, 7))
+	// A week without a span is skipped.
+	srv.AddPage(weeksDB, notiontest.Properties{"Name": notiontest.Title("9999 Someday")})
+
+	ctx := context.Background()
+	week, err := client.FetchWeekAt(ctx, monday.Add(9*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, current, week.ID)
+	assert.Equal(t, []string{article}, week.ReadingListIDs)
+
+	// All-day spans cover whole local days.
+	week, err = client.FetchWeekAt(ctx, monday.AddDate(0, 0, 7).Add(-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, current, week.ID, "Sunday night belongs to the week ending that day")
+	week, err = client.FetchWeekAt(ctx, monday.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	assert.Equal(t, next, week.ID)
+	assert.True(t, week.Start.Equal(monday.AddDate(0, 0, 7)))
+}
+
+func TestClient_FetchWeekAtMissing(t *testing.T) {
+	srv, client := fakeNotion(t)
+	addWeek(srv, time.Date(2020, 1, 6, 0, 0, 0, 0, time.Local))
+
+	_, err := client.FetchWeekAt(context.Background(), time.Date(2026, 10, 26, 12, 0, 0, 0, time.Local))
+	assert.ErrorContains(t, err, "no week found in Notion (looked for a \"🗓️ Span\" date containing 2026-10-26)")
+}
+
+func TestClient_FetchWeekAtTimedSpan(t *testing.T) {
+	srv, client := fakeNotion(t)
+	// A span with times keeps them, zone included.
+	id := srv.AddPage(weeksDB, notiontest.Properties{
+		"Name":    notiontest.Title("2026-10-19"),
+		"🗓️ Span": notiontest.Date("2026-10-19T06:00:00+09:00", "2026-10-26T06:00:00+09:00"),
+	})
+
+	ctx := context.Background()
+	week, err := client.FetchWeekAt(ctx, time.Date(2026, 10, 25, 20, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, id, week.ID)
+	assert.Equal(t, time.Date(2026, 10, 25, 21, 0, 0, 0, time.UTC), week.End.UTC())
+
+	// A time at UTC midnight is not a whole day.
+	id = srv.AddPage(weeksDB, notiontest.Properties{
+		"Name":    notiontest.Title("2026-11-02"),
+		"🗓️ Span": notiontest.Date("2026-11-02T00:00:00.000Z", "2026-11-09T00:00:00.000Z"),
+	})
+	week, err = client.FetchWeek(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), week.Start.UTC())
+	assert.Equal(t, time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC), week.End.UTC())
+}
+
+func TestClient_ListWeeks(t *testing.T) {
+	srv, client := fakeNotion(t)
+	srv.PageSize = 2
+
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	var ids []string
+	for i := -3; i <= 3; i++ {
+		ids = append(ids, addWeek(srv, monday.AddDate(0, 0, 7*i)))
+	}
+
+	// A week overlapping either end is included.
+	weeks, err := client.ListWeeks(context.Background(), monday.AddDate(0, 0, -4), monday.AddDate(0, 0, 8))
+	require.NoError(t, err)
+	var got []string
+	for _, w := range weeks {
+		got = append(got, w.ID)
+	}
+	assert.Equal(t, ids[2:5], got)
+}
+
+func TestClient_DoneArticleIDs(t *testing.T) {
+	srv, client := fakeNotion(t)
+	done := addArticle(srv, "done", true)
+	trashed := addArticle(srv, "trashed", true)
+	srv.Archive(trashed)
+	addArticle(srv, "open", false)
+
+	ids, err := client.DoneArticleIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{done}, ids)
+	assert.Equal(t, 1, countRequests(srv, "POST /v1/databases/"+readingDB+"/query"))
+}
+
+func TestClient_UpdateWeekReadingList(t *testing.T) {
+	srv, client := fakeNotion(t)
+
+	first := addArticle(srv, "first", false)
+	second := addArticle(srv, "second", false)
+	weekID := srv.AddPage(weeksDB, notiontest.Properties{
+		"Name":           notiontest.Title("2026-03-02"),
+		"🗓️ Span":        notiontest.Date("2026-03-02", "2026-03-08"),
+		"📑 Reading List": notiontest.Relation(first),
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.UpdateWeekReadingList(ctx, weekID, []string{first, second}))
+
+	page, ok := srv.Page(weekID)
+	require.True(t, ok)
+	assert.Equal(t, []string{first, second}, notiontest.RelationIDs(page.Properties["📑 Reading List"]))
+
+	week, err := client.FetchWeek(ctx, weekID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first, second}, week.ReadingListIDs)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local), week.Start)
+	assert.Equal(t, time.Date(2026, 3, 8, 23, 59, 59, 0, time.Local), week.End)
+
+	// A week Notion no longer shares is retried rather than rejected.
+	_, err = client.FetchWeek(ctx, "0badc0de0badc0de0badc0de0badc0de")
+	assert.ErrorContains(t, err, "Could not find page")
+	assert.NotErrorIs(t, err, readings.ErrRejected)
+
+	// Clearing the list sends an empty relation.
+	require.NoError(t, client.UpdateWeekReadingList(ctx, weekID, nil))
+	page, _ = srv.Page(weekID)
+	assert.Empty(t, notiontest.RelationIDs(page.Properties["📑 Reading List"]))
+}
+
+func TestClient_MarkDoneAndSetTags(t *testing.T) {
+	srv, client := fakeNotion(t)
+	id := addArticle(srv, "article", false, "go")
+
+	ctx := context.Background()
+	require.NoError(t, client.SetTags(ctx, id, []string{"go", "databases"}))
+	require.NoError(t, client.MarkDone(ctx, id))
+
+	page, ok := srv.Page(id)
+	require.True(t, ok)
+	assert.True(t, notiontest.Checked(page.Properties["Done"]))
+	assert.Equal(t, []string{"go", "databases"}, notiontest.Options(page.Properties["Tags"]))
+}
+
+func TestClient_CreateArticle(t *testing.T) {
+	srv, client := fakeNotion(t)
+
+	ctx := context.Background()
+	article, err := client.CreateArticle(ctx, readings.Article{
+		Title: "Go memory model",
+		URL:   "https://go.dev/ref/mem",
+		Tags:  []string{"go"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Go memory model", article.Title)
+	assert.Equal(t, "https://go.dev/ref/mem", article.URL)
+	assert.Equal(t, []string{"go"}, article.Tags)
+	assert.False(t, article.CreatedAt.IsZero())
+	assert.Equal(t, 1, countRequests(srv, "POST /v1/pages"))
+
+	articles, err := client.FetchArticles(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, article.ID, articles[0].ID)
+}
+
+func TestClient_RejectedEdits(t *testing.T) {
+	srv, client := fakeNotion(t)
+	id := addArticle(srv, "deleted", false)
+	srv.Archive(id)
+
+	err := client.MarkDone(context.Background(), id)
+	assert.ErrorIs(t, err, readings.ErrRejected)
+
+	// A page that is not found may only be unshared for now.
+	err = client.MarkDone(context.Background(), "00000000-0000-4000-8000-000000000000")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, readings.ErrRejected)
+
+	// A property missing from the database fails validation.
+	props := config.DefaultProperties()
+	props.Tags = "Labels"
+	renamed := NewClient("secret", readingDB, weeksDB,
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(RetryPolicy{}),
+		WithProperties(props),
+	)
+	live := addArticle(srv, "live", false)
+	err = renamed.SetTags(context.Background(), live, []string{"go"})
+	assert.ErrorIs(t, err, readings.ErrRejected)
+}
+
+func TestClient_PropertyTypes(t *testing.T) {
+	_, client := fakeNotion(t)
+
+	types, err := client.PropertyTypes(context.Background(), weeksDB)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Name":           "title",
+		"🗓️ Span":        "date",
+		"📑 Reading List": "relation",
+	}, types)
+}
@@ -0,0 +1,93 @@
+package notiontest
+
+func text(s string) []any {
+	return []any{map[string]any{
+		"type":       "text",
+		"text":       map[string]any{"content": s},
+		"plain_text": s,
+	}}
+}
+
+// Title returns a title property.
+func Title(s string) Property {
+	return Property{"type": "title", "title": text(s)}
+}
+
+// RichText returns a rich text property.
+func RichText(s string) Property {
+	return Property{"type": "rich_text", "rich_text": text(s)}
+}
+
+// URL returns a URL property.
+func URL(s string) Property {
+	return Property{"type": "url", "url": s}
+}
+
+// MultiSelect returns a multi-select property with the given options.
+func MultiSelect(names ...string) Property {
+	options := make([]any, len(names))
+	for i, name := range names {
+		options[i] = map[string]any{"name": name}
+	}
+	return Property{"type": "multi_select", "multi_select": options}
+}
+
+// Checkbox returns a checkbox property.
+func Checkbox(checked bool) Property {
+	return Property{"type": "checkbox", "checkbox": checked}
+}
+
+// Number returns a number property.
+func Number(n float64) Property {
+	return Property{"type": "number", "number": n}
+}
+
+// Date returns a date property. Dates are "2006-01-02" or RFC 3339; an
+// empty end leaves it unset.
+func Date(start, end string) Property {
+	date := map[string]any{"start": start, "end": nil}
+	if end != "" {
+		date["end"] = end
+	}
+	return Property{"type": "date", "date": date}
+}
+
+// Relation returns a relation property to the given pages.
+func Relation(pageIDs ...string) Property {
+	relations := make([]any, len(pageIDs))
+	for i, id := range pageIDs {
+		relations[i] = map[string]any{"id": id}
+	}
+	return Property{"type": "relation", "relation": relations, "has_more": false}
+}
+
+// RelationIDs returns the page IDs of a relation property.
+func RelationIDs(prop Property) []string {
+	var ids []string
+	items, _ := prop["relation"].([]any)
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			id, _ := m["id"].(string)
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Checked reports whether a checkbox property is ticked.
+func Checked(prop Property) bool {
+	return prop["checkbox"] == true
+}
+
+// Options returns the option names of a multi-select property.
+func Options(prop Property) []string {
+	var names []string
+	items, _ := prop["multi_select"].([]any)
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			name, _ := m["name"].(string)
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,587 @@
+// Package notiontest provides an in-memory stand-in for the parts of the
+// Notion API the app uses, for end-to-end tests of the real client.
+package notiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Property is a page property in Notion's JSON form, e.g.
+// {"type": "checkbox", "checkbox": true}. Build them with Title, URL and
+// the other constructors.
+type Property map[string]any
+
+// Properties maps property names to values.
+type Properties map[string]Property
+
+// Page is a stored page.
+type Page struct {
+	ID             string
+	DatabaseID     string
+	CreatedTime    time.Time
+	LastEditedTime time.Time
+	Archived       bool
+	Properties     Properties
+}
+
+// Server is a fake Notion API. Pages are kept in the order they were added.
+type Server struct {
+	*httptest.Server
+
+	// Token, if set, must be sent as the bearer token.
+	Token string
+	// PageSize caps the results per query response to exercise pagination.
+	PageSize int
+	// Now stamps created and edited times.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	databases map[string]map[string]string // Property types by name
+	pages     []*Page
+	requests  []string
+	nextID    int
+}
+
+// NewServer starts a fake Notion API. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		PageSize:  100,
+		Now:       time.Now,
+		databases: make(map[string]map[string]string),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddDatabase creates a database with the given property types, such as
+// {"Name": "title", "Done": "checkbox"}.
+func (s *Server) AddDatabase(id string, schema map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.databases[id] = schema
+}
+
+// AddPage creates a page in a database and returns its ID.
+func (s *Server) AddPage(databaseID string, props Properties) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	now := s.Now().UTC()
+	p := &Page{
+		ID:             fmt.Sprintf("%08x-0000-4000-8000-%012x", s.nextID, s.nextID),
+		DatabaseID:     databaseID,
+		CreatedTime:    now,
+		LastEditedTime: now,
+		Properties:     Properties{},
+	}
+	for name, prop := range props {
+		p.Properties[name] = prop
+	}
+	s.pages = append(s.pages, p)
+	return p.ID
+}
+
+// SetProperty edits a page as if by hand in Notion.
+func (s *Server) SetProperty(pageID, name string, prop Property) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.find(pageID); p != nil {
+		p.Properties[name] = prop
+		p.LastEditedTime = s.Now().UTC()
+	}
+}
+
+// Archive moves a page to the trash, hiding it from queries.
+func (s *Server) Archive(pageID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p := s.find(pageID); p != nil {
+		p.Archived = true
+		p.LastEditedTime = s.Now().UTC()
+	}
+}
+
+// Page returns a copy of a stored page.
+func (s *Server) Page(pageID string) (Page, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.find(pageID)
+	if p == nil {
+		return Page{}, false
+	}
+	c := *p
+	c.Properties = Properties{}
+	for k, v := range p.Properties {
+		c.Properties[k] = v
+	}
+	return c, true
+}
+
+// Requests returns the method and path of every request received, such as
+// "PATCH /v1/pages/<id>".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) find(id string) *Page {
+	id = normalizeID(id)
+	for _, p := range s.pages {
+		if normalizeID(p.ID) == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func normalizeID(id string) string {
+	return strings.ReplaceAll(id, "-", "")
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "API token is invalid.")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 4 && parts[1] == "databases" && parts[3] == "query" && r.Method == http.MethodPost:
+		s.queryDatabase(w, r, parts[2])
+	case len(parts) == 3 && parts[1] == "databases" && r.Method == http.MethodGet:
+		s.getDatabase(w, parts[2])
+	case len(parts) == 2 && parts[1] == "pages" && r.Method == http.MethodPost:
+		s.createPage(w, r)
+	case len(parts) == 3 && parts[1] == "pages" && r.Method == http.MethodGet:
+		s.getPage(w, parts[2])
+	case len(parts) == 3 && parts[1] == "pages" && r.Method == http.MethodPatch:
+		s.updatePage(w, r, parts[2])
+	default:
+		writeError(w, http.StatusBadRequest, "invalid_request_url", "Invalid request URL.")
+	}
+}
+
+type queryRequest struct {
+	Filter      map[string]any `json:"filter"`
+	Sorts       []sortObject   `json:"sorts"`
+	StartCursor string         `json:"start_cursor"`
+	PageSize    int            `json:"page_size"`
+}
+
+type sortObject struct {
+	Property  string `json:"property"`
+	Timestamp string `json:"timestamp"`
+	Direction string `json:"direction"`
+}
+
+func (s *Server) queryDatabase(w http.ResponseWriter, r *http.Request, databaseID string) {
+	if _, ok := s.databases[databaseID]; !ok {
+		writeError(w, http.StatusNotFound, "object_not_found", "Could not find database with ID: "+databaseID+".")
+		return
+	}
+
+	var req queryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+
+	var matched []*Page
+	for _, p := range s.pages {
+		if p.DatabaseID != databaseID || p.Archived {
+			continue
+		}
+		ok, err := matches(p, req.Filter)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+			return
+		}
+		if ok {
+			matched = append(matched, p)
+		}
+	}
+
+	for i := len(req.Sorts) - 1; i >= 0; i-- {
+		so := req.Sorts[i]
+		sort.SliceStable(matched, func(a, b int) bool {
+			ka, kb := sortKey(matched[a], so), sortKey(matched[b], so)
+			if so.Direction == "descending" {
+				return ka > kb
+			}
+			return ka < kb
+		})
+	}
+
+	start := 0
+	if req.StartCursor != "" {
+		start = -1
+		for i, p := range matched {
+			if p.ID == req.StartCursor {
+				start = i
+			}
+		}
+		if start < 0 {
+			writeError(w, http.StatusBadRequest, "validation_error", "start_cursor is invalid.")
+			return
+		}
+	}
+
+	size := s.PageSize
+	if req.PageSize > 0 && req.PageSize < size {
+		size = req.PageSize
+	}
+	end := start + size
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	results := make([]any, 0, end-start)
+	for _, p := range matched[start:end] {
+		results = append(results, pageJSON(p))
+	}
+	var next any
+	if end < len(matched) {
+		next = matched[end].ID
+	}
+
+	writeJSON(w, map[string]any{
+		"object":      "list",
+		"results":     results,
+		"has_more":    end < len(matched),
+		"next_cursor": next,
+	})
+}
+
+func (s *Server) getDatabase(w http.ResponseWriter, databaseID string) {
+	schema, ok := s.databases[databaseID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "object_not_found", "Could not find database with ID: "+databaseID+".")
+		return
+	}
+
+	props := make(map[string]any, len(schema))
+	for name, typ := range schema {
+		props[name] = map[string]any{"id": name, "name": name, "type": typ, typ: map[string]any{}}
+	}
+	writeJSON(w, map[string]any{
+		"object":     "database",
+		"id":         databaseID,
+		"title":      []any{},
+		"properties": props,
+	})
+}
+
+func (s *Server) getPage(w http.ResponseWriter, pageID string) {
+	p := s.find(pageID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "object_not_found", "Could not find page with ID: "+pageID+".")
+		return
+	}
+	writeJSON(w, pageJSON(p))
+}
+
+func (s *Server) updatePage(w http.ResponseWriter, r *http.Request, pageID string) {
+	p := s.find(pageID)
+	if p == nil {
+		writeError(w, http.StatusNotFound, "object_not_found", "Could not find page with ID: "+pageID+".")
+		return
+	}
+	if p.Archived {
+		writeError(w, http.StatusBadRequest, "validation_error", "Can't edit block that is archived. You must unarchive the block before editing.")
+		return
+	}
+
+	var req struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Archived   *bool                     `json:"archived"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+
+	if err := setProperties(p, s.databases[p.DatabaseID], req.Properties); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	if req.Archived != nil {
+		p.Archived = *req.Archived
+	}
+	p.LastEditedTime = s.Now().UTC()
+
+	writeJSON(w, pageJSON(p))
+}
+
+func (s *Server) createPage(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Parent struct {
+			DatabaseID string `json:"database_id"`
+		} `json:"parent"`
+		Properties map[string]map[string]any `json:"properties"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json", err.Error())
+		return
+	}
+
+	var databaseID string
+	for id := range s.databases {
+		if normalizeID(id) == normalizeID(req.Parent.DatabaseID) {
+			databaseID = id
+		}
+	}
+	if databaseID == "" {
+		writeError(w, http.StatusNotFound, "object_not_found", "Could not find database with ID: "+req.Parent.DatabaseID+".")
+		return
+	}
+
+	s.nextID++
+	now := s.Now().UTC()
+	p := &Page{
+		ID:             fmt.Sprintf("%08x-0000-4000-8000-%012x", s.nextID, s.nextID),
+		DatabaseID:     databaseID,
+		CreatedTime:    now,
+		LastEditedTime: now,
+		Properties:     Properties{},
+	}
+	if err := setProperties(p, s.databases[databaseID], req.Properties); err != nil {
+		writeError(w, http.StatusBadRequest, "validation_error", err.Error())
+		return
+	}
+	s.pages = append(s.pages, p)
+
+	writeJSON(w, pageJSON(p))
+}
+
+// setProperties writes property values sent by a client, checking them
+// against the database schema.
+func setProperties(p *Page, schema map[string]string, values map[string]map[string]any) error {
+	for name, value := range values {
+		typ, ok := schema[name]
+		if !ok {
+			return fmt.Errorf("%s is not a property that exists.", name)
+		}
+		if _, ok := value[typ]; !ok {
+			return fmt.Errorf("%s is expected to be %s.", name, typ)
+		}
+	}
+	for name, value := range values {
+		typ := schema[name]
+		p.Properties[name] = Property{"type": typ, typ: normalizeValue(typ, value[typ])}
+	}
+	return nil
+}
+
+// normalizeValue fills in what Notion adds to values on write.
+func normalizeValue(typ string, value any) any {
+	if typ != "title" && typ != "rich_text" {
+		return value
+	}
+	items, _ := value.([]any)
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			if text, ok := m["text"].(map[string]any); ok {
+				m["plain_text"] = text["content"]
+			}
+		}
+	}
+	return items
+}
+
+func pageJSON(p *Page) map[string]any {
+	props := make(map[string]any, len(p.Properties))
+	for name, prop := range p.Properties {
+		withID := map[string]any{"id": name}
+		for k, v := range prop {
+			withID[k] = v
+		}
+		props[name] = withID
+	}
+	return map[string]any{
+		"object":           "page",
+		"id":               p.ID,
+		"created_time":     p.CreatedTime.Format(time.RFC3339Nano),
+		"last_edited_time": p.LastEditedTime.Format(time.RFC3339Nano),
+		"archived":         p.Archived,
+		"parent":           map[string]any{"type": "database_id", "database_id": p.DatabaseID},
+		"url":              "https://www.notion.so/" + normalizeID(p.ID),
+		"properties":       props,
+	}
+}
+
+// matches evaluates the subset of Notion filters the client sends.
+func matches(p *Page, filter map[string]any) (bool, error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+
+	for _, op := range []string{"and", "or"} {
+		list, ok := filter[op].([]any)
+		if !ok {
+			continue
+		}
+		for _, f := range list {
+			sub, _ := f.(map[string]any)
+			ok, err := matches(p, sub)
+			if err != nil {
+				return false, err
+			}
+			if op == "or" && ok {
+				return true, nil
+			}
+			if op == "and" && !ok {
+				return false, nil
+			}
+		}
+		return op == "and", nil
+	}
+
+	if ts, ok := filter["timestamp"].(string); ok {
+		var value time.Time
+		switch ts {
+		case "last_edited_time":
+			value = p.LastEditedTime
+		case "created_time":
+			value = p.CreatedTime
+		default:
+			return false, fmt.Errorf("unsupported timestamp %q", ts)
+		}
+		cond, _ := filter[ts].(map[string]any)
+		return compareDate(value, cond)
+	}
+
+	name, ok := filter["property"].(string)
+	if !ok {
+		return false, fmt.Errorf("unsupported filter %v", filter)
+	}
+	prop := p.Properties[name]
+
+	if cond, ok := filter["checkbox"].(map[string]any); ok {
+		checked, _ := prop["checkbox"].(bool)
+		if want, ok := cond["equals"].(bool); ok {
+			return checked == want, nil
+		}
+		if want, ok := cond["does_not_equal"].(bool); ok {
+			return checked != want, nil
+		}
+		return false, fmt.Errorf("unsupported checkbox filter %v", cond)
+	}
+	if cond, ok := filter["date"].(map[string]any); ok {
+		date, _ := prop["date"].(map[string]any)
+		start, _ := date["start"].(string)
+		if start == "" {
+			return cond["is_empty"] == true, nil
+		}
+		t, err := parseDate(start)
+		if err != nil {
+			return false, err
+		}
+		return compareDate(t, cond)
+	}
+	return false, fmt.Errorf("unsupported filter on %q", name)
+}
+
+func compareDate(value time.Time, cond map[string]any) (bool, error) {
+	for op, raw := range cond {
+		s, _ := raw.(string)
+		t, err := parseDate(s)
+		if err != nil {
+			return false, err
+		}
+		var ok bool
+		switch op {
+		case "equals":
+			ok = value.Equal(t)
+		case "before":
+			ok = value.Before(t)
+		case "after":
+			ok = value.After(t)
+		case "on_or_before":
+			ok = !value.After(t)
+		case "on_or_after":
+			ok = !value.Before(t)
+		default:
+			return false, fmt.Errorf("unsupported date condition %q", op)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+func sortKey(p *Page, so sortObject) string {
+	switch so.Timestamp {
+	case "created_time":
+		return p.CreatedTime.Format(time.RFC3339Nano)
+	case "last_edited_time":
+		return p.LastEditedTime.Format(time.RFC3339Nano)
+	}
+
+	prop := p.Properties[so.Property]
+	switch prop["type"] {
+	case "title", "rich_text":
+		return plainText(prop)
+	case "date":
+		date, _ := prop["date"].(map[string]any)
+		start, _ := date["start"].(string)
+		return start
+	case "number":
+		n, _ := prop["number"].(float64)
+		return fmt.Sprintf("%020.6f", n)
+	case "checkbox":
+		return strconv.FormatBool(prop["checkbox"] == true)
+	}
+	return ""
+}
+
+// plainText concatenates a title or rich text property.
+func plainText(prop Property) string {
+	typ, _ := prop["type"].(string)
+	var b strings.Builder
+	items, _ := prop[typ].([]any)
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			s, _ := m["plain_text"].(string)
+			b.WriteString(s)
+		}
+	}
+	return b.String()
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"object":  "error",
+		"status":  status,
+		"code":    code,
+		"message": message,
+	})
+}
@@ -0,0 +1,250 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how requests to Notion are spaced and retried.
+type RetryPolicy struct {
+	RequestsPerSecond float64       // Request budget shared by all calls; 0 disables spacing
+	MaxRetries        int           // Retries after the first attempt
+	BaseDelay         time.Duration // First backoff, doubled on every retry
+	MaxDelay          time.Duration // Upper bound for backoff
+}
+
+// DefaultRetryPolicy stays within Notion's average limit of three requests
+// per second.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		RequestsPerSecond: 3,
+		MaxRetries:        5,
+		BaseDelay:         500 * time.Millisecond,
+		MaxDelay:          30 * time.Second,
+	}
+}
+
+// retryTransport spaces requests to the configured budget and retries rate
+// limited (429) and transient server errors, honoring Retry-After. Page
+// creation is only retried on 429, since after a network or server error
+// the page may have been created already.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+
+	mu   sync.Mutex
+	next time.Time // Earliest start of the next request
+
+	// Replaced in tests
+	sleep  func(ctx context.Context, d time.Duration) error
+	jitter func(d time.Duration) time.Duration
+}
+
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		base:   base,
+		policy: policy,
+		sleep:  sleepContext,
+		jitter: equalJitter,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		if err := t.sleep(ctx, t.reserve()); err != nil {
+			return nil, err
+		}
+
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.policy.MaxRetries || !retryable(ctx, req, resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = d
+			}
+			// Drain so the connection can be reused.
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		// A 429 applies to the whole integration, so hold back every
+		// request, not just this one.
+		t.delayAll(delay)
+	}
+}
+
+// reserve claims the next request slot and returns how long to wait for it.
+func (t *retryTransport) reserve() time.Duration {
+	var interval time.Duration
+	if t.policy.RequestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / t.policy.RequestsPerSecond)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	wait := t.next.Sub(now)
+	t.next = t.next.Add(interval)
+	return wait
+}
+
+// delayAll pushes the next request slot at least d into the future.
+func (t *retryTransport) delayAll(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if until := time.Now().Add(d); t.next.Before(until) {
+		t.next = until
+	}
+}
+
+// backoff returns the jittered exponential delay before retry attempt+1.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.policy.BaseDelay << attempt
+	if d <= 0 || d > t.policy.MaxDelay {
+		d = t.policy.MaxDelay
+	}
+	return t.jitter(d)
+}
+
+// rewind returns the request to send for the given attempt, with a fresh
+// copy of the body for retries.
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("cannot retry request with a body that cannot be replayed")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+func retryable(ctx context.Context, req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// Network errors are worth retrying; a cancelled request is not.
+		return ctx.Err() == nil && !createsPage(req)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// Notion turned the request away without acting on it.
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return !createsPage(req)
+	}
+	return false
+}
+
+// createsPage reports whether req creates a page, which sending twice would
+// do twice. Other POSTs to Notion, such as database queries, only read.
+func createsPage(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.TrimSuffix(req.URL.Path, "/") == "/v1/pages"
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		d := time.Until(at)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// equalJitter returns a random duration between d/2 and d, so clients that
+// failed together do not retry together.
+func equalJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rebase sends requests to another host, keeping their path below the base
+// URL's path.
+type rebase struct {
+	base *url.URL
+	next http.RoundTripper
+}
+
+func (r rebase) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := r.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.base.Scheme
+	req.URL.Host = r.base.Host
+	req.URL.Path = strings.TrimSuffix(r.base.Path, "/") + req.URL.Path
+	req.URL.RawPath = ""
+	req.Host = ""
+	return next.RoundTrip(req)
+}
+
+// failTransport fails every request with err without sending it.
+type failTransport struct {
+	err error
+}
+
+func (f failTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, f.err
+}
@@ -0,0 +1,226 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSleeper replaces real sleeps and records the requested waits.
+type recordingSleeper struct {
+	mu    sync.Mutex
+	waits []time.Duration
+}
+
+func (r *recordingSleeper) sleep(ctx context.Context, d time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d > 0 {
+		r.waits = append(r.waits, d)
+	}
+	return ctx.Err()
+}
+
+// scripted serves the given status codes in order, then 200, recording
+// the request bodies.
+func scripted(t *testing.T, statuses []int, headers map[string]string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		n := len(bodies)
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+
+		if n < len(statuses) {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(statuses[n])
+			fmt.Fprintf(w, `{"object":"error","status":%d,"message":%q}`, statuses[n], http.StatusText(statuses[n]))
+			return
+		}
+		io.WriteString(w, `{"object":"list","results":[],"has_more":false}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func testTransport(policy RetryPolicy) (*retryTransport, *recordingSleeper) {
+	sleeper := &recordingSleeper{}
+	tr := newRetryTransport(nil, policy)
+	tr.sleep = sleeper.sleep
+	tr.jitter = func(d time.Duration) time.Duration { return d }
+	return tr, sleeper
+}
+
+func post(t *testing.T, tr http.RoundTripper, ctx context.Context, url, body string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	return (&http.Client{Transport: tr}).Do(req)
+}
+
+func TestRetryTransport_HonorsRetryAfter(t *testing.T) {
+	srv, bodies := scripted(t, []int{429, 429}, map[string]string{"Retry-After": "2"})
+	tr, sleeper := testTransport(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+
+	resp, err := post(t, tr, context.Background(), srv.URL, `{"page_size":100}`)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{`{"page_size":100}`, `{"page_size":100}`, `{"page_size":100}`}, *bodies)
+	require.Len(t, sleeper.waits, 2)
+	for _, w := range sleeper.waits {
+		assert.InDelta(t, 2*time.Second, w, float64(100*time.Millisecond))
+	}
+}
+
+func TestRetryTransport_BacksOffExponentially(t *testing.T) {
+	srv, bodies := scripted(t, []int{503, 502, 500}, nil)
+	tr, sleeper := testTransport(RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 250 * time.Millisecond})
+
+	resp, err := post(t, tr, context.Background(), srv.URL, "{}")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, *bodies, 4)
+	require.Len(t, sleeper.waits, 3)
+	assert.InDelta(t, 100*time.Millisecond, sleeper.waits[0], float64(20*time.Millisecond))
+	assert.InDelta(t, 200*time.Millisecond, sleeper.waits[1], float64(20*time.Millisecond))
+	assert.InDelta(t, 250*time.Millisecond, sleeper.waits[2], float64(20*time.Millisecond)) // Capped
+}
+
+func TestRetryTransport_GivesUp(t *testing.T) {
+	srv, bodies := scripted(t, []int{500, 500, 500, 500}, nil)
+	tr, _ := testTransport(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	resp, err := post(t, tr, context.Background(), srv.URL, "{}")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Len(t, *bodies, 3)
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	srv, bodies := scripted(t, []int{400}, nil)
+	tr, sleeper := testTransport(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	resp, err := post(t, tr, context.Background(), srv.URL, "{}")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+	assert.Empty(t, sleeper.waits)
+}
+
+func TestRetryTransport_RetriesPageCreationOnlyWhenRateLimited(t *testing.T) {
+	srv, bodies := scripted(t, []int{429, 503}, nil)
+	tr, _ := testTransport(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	// The 503 may have come after the page was created.
+	resp, err := post(t, tr, context.Background(), srv.URL+"/v1/pages", "{}")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Len(t, *bodies, 2)
+
+	// So may a dropped connection.
+	calls := 0
+	tr.base = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("connection reset")
+	})
+	_, err = post(t, tr, context.Background(), srv.URL+"/v1/pages", "{}")
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, 1, calls)
+
+	// Queries are safe to resend.
+	_, err = post(t, tr, context.Background(), srv.URL+"/v1/databases/db/query", "{}")
+	assert.Error(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestRetryTransport_SpacesRequests(t *testing.T) {
+	srv, _ := scripted(t, nil, nil)
+	tr, sleeper := testTransport(RetryPolicy{RequestsPerSecond: 10})
+
+	for i := 0; i < 3; i++ {
+		resp, err := post(t, tr, context.Background(), srv.URL, "{}")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// The sleeper does not advance the clock, so each request queues behind
+	// the slots reserved before it.
+	require.Len(t, sleeper.waits, 2)
+	assert.InDelta(t, 100*time.Millisecond, sleeper.waits[0], float64(20*time.Millisecond))
+	assert.InDelta(t, 200*time.Millisecond, sleeper.waits[1], float64(20*time.Millisecond))
+}
+
+func TestRetryTransport_ContextCancellation(t *testing.T) {
+	srv, bodies := scripted(t, []int{429, 429, 429}, map[string]string{"Retry-After": "30"})
+	tr := newRetryTransport(nil, RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := post(t, tr, ctx, srv.URL, "{}")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestClient_RetriesRateLimitedQuery(t *testing.T) {
+	srv, bodies := scripted(t, []int{429}, map[string]string{"Retry-After": "0"})
+
+	client := NewClient("secret", "reading-db", "weeks-db",
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+
+	articles, err := client.FetchArticles(context.Background(), time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, articles)
+
+	// The query body was sent again in full.
+	require.Len(t, *bodies, 2)
+	assert.Contains(t, (*bodies)[1], `"property":"Done"`)
+	assert.Equal(t, (*bodies)[0], (*bodies)[1])
+}
+
+func TestClient_InvalidBaseURLFailsRequests(t *testing.T) {
+	var sent []*http.Request
+	client := NewClient("secret", "reading-db", "weeks-db",
+		WithTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req)
+			return nil, errors.New("unexpected request")
+		})),
+		WithBaseURL("localhost:8080"),
+	)
+
+	_, err := client.FetchArticles(context.Background(), time.Time{})
+	assert.ErrorContains(t, err, `invalid Notion base URL "localhost:8080"`)
+	assert.Empty(t, sent, "Nothing reaches the real API")
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
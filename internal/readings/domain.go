@@ -2,35 +2,139 @@ package readings
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrNotFound is returned when an article is not in the local cache.
+var ErrNotFound = errors.New("article not found")
+
+// ErrDuplicate is returned when adding an article whose URL is already in
+// the local cache.
+var ErrDuplicate = errors.New("article already saved")
+
 // Article represents a reading item.
 type Article struct {
-	ID        string    `db:"id"`
-	Title     string    `db:"title"`
-	URL       string    `db:"url"`
-	Tags      []string  `db:"-"` // Handled via custom scanner/valuer or JSON string in DB
-	FetchedAt time.Time `db:"fetched_at"`
+	ID        string    `db:"id" json:"id"`
+	Title     string    `db:"title" json:"title"`
+	URL       string    `db:"url" json:"url"`
+	Tags      []string  `db:"-" json:"tags"` // Handled via custom scanner/valuer or JSON string in DB
+	FetchedAt time.Time `db:"fetched_at" json:"fetched_at"`
+	Done      bool      `db:"-" json:"-"` // Set by Notion; done articles are never cached
+
+	// Metadata shown in the detail view
+	CreatedAt      time.Time `db:"created_at" json:"created_at"` // When the page was added in Notion
+	Notes          string    `db:"notes" json:"notes,omitempty"`
+	ReadingMinutes int       `db:"reading_minutes" json:"reading_minutes,omitempty"` // Estimated; 0 if unknown
+	Priority       int       `db:"priority" json:"priority,omitempty"`               // From Notion; higher is more urgent
+
+	// SurfacedAt is when the article was last shown as a suggestion, from
+	// the history, or zero if it never was.
+	SurfacedAt time.Time `db:"surfaced_at" json:"-"`
+}
+
+// RemovalReason records why an article left the local cache.
+type RemovalReason string
+
+const (
+	// RemovedDone means the article was marked Done in Notion.
+	RemovedDone RemovalReason = "done"
+	// RemovedMissing means a full sync no longer returned the article and
+	// Notion no longer has it in the reading database.
+	RemovedMissing RemovalReason = "missing"
+)
+
+// Removal is an article that left the local cache. Removed articles are
+// kept for the history and statistics.
+type Removal struct {
+	Article
+	At     time.Time
+	Reason RemovalReason
 }
 
 // Week represents a weekly planning entry.
 type Week struct {
 	ID             string
+	Start          time.Time
+	End            time.Time // Inclusive
 	ReadingListIDs []string
 }
 
+// Contains reports whether t falls within the week.
+func (w Week) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && !t.After(w.End)
+}
+
 // Repository defines the interface for local storage.
 type Repository interface {
 	// SaveUpsert saves articles to the local cache, updating existing ones.
 	SaveUpsert(ctx context.Context, articles []Article) error
 
-	// GetRandom returns 'count' random articles, optionally filtered by tag.
-	GetRandom(ctx context.Context, count int, tag string) ([]Article, error)
+	// GetMatching returns the articles matching the tag filter, in no
+	// particular order.
+	GetMatching(ctx context.Context, filter TagFilter) ([]Article, error)
 
 	// GetAll returns all articles.
 	GetAll(ctx context.Context) ([]Article, error)
 
+	// Search returns up to limit articles whose title, URL or tags contain
+	// every word of query as a prefix, best matches first.
+	Search(ctx context.Context, query string, limit int) ([]Article, error)
+
+	// TagCounts returns how many articles carry each tag, most used first.
+	// Tags differing only in case are counted together.
+	TagCounts(ctx context.Context) ([]TagCount, error)
+
+	// Find returns the article whose ID or URL matches ref, or ErrNotFound.
+	// IDs match with or without dashes.
+	Find(ctx context.Context, ref string) (*Article, error)
+
+	// RecordEvents adds events to the history. Shown events also update
+	// the articles' SurfacedAt.
+	RecordEvents(ctx context.Context, events []Event) error
+
+	// History returns the events matching filter, newest first, with the
+	// titles of the articles that are or were cached.
+	History(ctx context.Context, filter HistoryFilter) ([]Event, error)
+
+	// Removed returns the articles removed from the cache, with when and
+	// why. Articles saved again since are not included.
+	Removed(ctx context.Context) ([]Removal, error)
+
+	// MarkRemoved tombstones the given articles so they are no longer
+	// returned. Saving an article again brings it back.
+	MarkRemoved(ctx context.Context, ids []string, reason RemovalReason) error
+
+	// GetSyncCursor returns when the given Notion database was last synced
+	// successfully, or the zero time if it never was.
+	GetSyncCursor(ctx context.Context, databaseID string) (time.Time, error)
+
+	// SetSyncCursor records a successful sync of the given Notion database.
+	SetSyncCursor(ctx context.Context, databaseID string, syncedAt time.Time) error
+
+	// SaveWeek stores a local copy of a week, replacing any previous one.
+	SaveWeek(ctx context.Context, week Week) error
+
+	// WeekAt returns the stored week containing t, or ErrNotFound.
+	WeekAt(ctx context.Context, t time.Time) (*Week, error)
+
+	// Weeks returns the stored weeks overlapping from through to, earliest
+	// first.
+	Weeks(ctx context.Context, from, to time.Time) ([]Week, error)
+
+	// Enqueue adds a change to the outbox.
+	Enqueue(ctx context.Context, op PendingOp) error
+
+	// PendingOps returns the outbox, oldest first.
+	PendingOps(ctx context.Context) ([]PendingOp, error)
+
+	// RecordOpFailure counts a failed attempt to apply an op. Failed ops are
+	// not retried.
+	RecordOpFailure(ctx context.Context, id int64, message string, failed bool) error
+
+	// DeleteOps removes ops from the outbox, returning how many existed.
+	DeleteOps(ctx context.Context, ids []int64) (int, error)
+
 	// Close closes the storage connection.
 	Close() error
 }
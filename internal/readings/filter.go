@@ -0,0 +1,57 @@
+package readings
+
+import "strings"
+
+// TagFilter selects articles by tag. Tags match exactly, ignoring case.
+type TagFilter struct {
+	Include  []string // Articles with any of these tags; empty means all articles
+	Exclude  []string // Articles with none of these tags
+	MatchAll bool     // Require all Include tags instead of any
+}
+
+// TagCount is the number of cached articles carrying a tag.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagKey is the case-folded form tags are matched and grouped by, the same
+// in the cache and in memory. It folds more than ASCII, unlike SQLite's
+// lower().
+func TagKey(tag string) string {
+	return strings.ToLower(tag)
+}
+
+// IsEmpty reports whether the filter lets every article through.
+func (f TagFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Matches reports whether the article passes the filter.
+func (f TagFilter) Matches(a Article) bool {
+	for _, tag := range f.Exclude {
+		if hasTag(a, tag) {
+			return false
+		}
+	}
+
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, tag := range f.Include {
+		if hasTag(a, tag) != f.MatchAll {
+			// Any-of stops at the first hit, all-of at the first miss.
+			return !f.MatchAll
+		}
+	}
+	return f.MatchAll
+}
+
+func hasTag(a Article, tag string) bool {
+	for _, t := range a.Tags {
+		if TagKey(t) == TagKey(tag) {
+			return true
+		}
+	}
+	return false
+}
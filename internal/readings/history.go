@@ -0,0 +1,90 @@
+package readings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventKind identifies something that happened to an article locally.
+type EventKind string
+
+const (
+	EventShown   EventKind = "shown"   // Suggested by a plan or a picked list
+	EventPlanned EventKind = "planned" // Added to a week's reading list
+	EventOpened  EventKind = "opened"  // Opened in the browser
+	EventDone    EventKind = "done"    // Marked done
+)
+
+// EventKinds lists every kind of event.
+var EventKinds = []EventKind{EventShown, EventPlanned, EventOpened, EventDone}
+
+// ParseEventKind returns the event kind with the given name.
+func ParseEventKind(name string) (EventKind, error) {
+	names := make([]string, len(EventKinds))
+	for i, kind := range EventKinds {
+		if strings.EqualFold(string(kind), name) {
+			return kind, nil
+		}
+		names[i] = string(kind)
+	}
+	return "", fmt.Errorf("unknown event %q (want one of %s)", name, strings.Join(names, ", "))
+}
+
+// Event is an entry of the local history.
+type Event struct {
+	ID        int64     `json:"id"`
+	Kind      EventKind `json:"kind"`
+	ArticleID string    `json:"article_id"`
+	Title     string    `json:"title,omitempty"`   // Read back from the cache; empty if the article was never cached
+	WeekID    string    `json:"week_id,omitempty"` // For EventPlanned
+	At        time.Time `json:"at"`
+}
+
+// HistoryFilter selects history events. Zero fields match everything.
+type HistoryFilter struct {
+	Kinds   []EventKind // Any of these
+	Article string      // ID, with or without dashes, or URL
+	Since   time.Time   // Inclusive
+	Until   time.Time   // Exclusive
+	Limit   int         // The newest this many
+}
+
+// History returns the events matching filter, newest first.
+func (s *Service) History(ctx context.Context, filter HistoryFilter) ([]Event, error) {
+	events, err := s.repo.History(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return events, nil
+}
+
+// RecordOpened records that an article was opened in the browser.
+func (s *Service) RecordOpened(ctx context.Context, articleID string) error {
+	return s.record(ctx, EventOpened, "", articleID)
+}
+
+// record adds an event of the given kind for each article to the history.
+func (s *Service) record(ctx context.Context, kind EventKind, weekID string, articleIDs ...string) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	events := make([]Event, len(articleIDs))
+	for i, id := range articleIDs {
+		events[i] = Event{Kind: kind, ArticleID: id, WeekID: weekID, At: now}
+	}
+	if err := s.repo.RecordEvents(ctx, events); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+	return nil
+}
+
+func articleIDs(articles []Article) []string {
+	ids := make([]string, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	return ids
+}
@@ -0,0 +1,251 @@
+package readings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrRejected marks errors Notion returns for requests that can never
+// succeed, such as editing a deleted page. Queued changes failing with it
+// are not retried.
+var ErrRejected = errors.New("rejected by Notion")
+
+// OpKind identifies a change queued for Notion.
+type OpKind string
+
+const (
+	OpAddToWeek      OpKind = "add_to_week"
+	OpRemoveFromWeek OpKind = "remove_from_week"
+	OpMarkDone       OpKind = "mark_done"
+	OpSetTags        OpKind = "set_tags"
+	OpMoveInWeek     OpKind = "move_in_week"
+)
+
+// PendingOp is a change applied to the local cache that has not reached
+// Notion yet.
+type PendingOp struct {
+	ID        int64     `json:"id"`
+	Kind      OpKind    `json:"kind"`
+	ArticleID string    `json:"article_id"`
+	WeekID    string    `json:"week_id,omitempty"`  // For week edits
+	Tags      []string  `json:"tags,omitempty"`     // For OpSetTags
+	Position  int       `json:"position,omitempty"` // For OpMoveInWeek, 0-based
+	CreatedAt time.Time `json:"created_at"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	Failed    bool      `json:"failed"` // Rejected by Notion; kept until discarded
+}
+
+// Flush pushes queued changes to Notion, oldest first, and returns how many
+// were applied. It stops at the first error that may go away by itself, such
+// as a network failure, so later changes cannot overtake earlier ones.
+// Changes Notion rejects are marked failed and skipped from then on.
+func (s *Service) Flush(ctx context.Context) (int, error) {
+	ops, err := s.repo.PendingOps(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	applied := 0
+	for i := 0; i < len(ops); {
+		if ops[i].Failed {
+			i++
+			continue
+		}
+		batch := nextBatch(ops[i:])
+		i += len(batch)
+
+		ids := make([]int64, len(batch))
+		for j, op := range batch {
+			ids[j] = op.ID
+		}
+
+		err := s.apply(ctx, batch)
+		if err == nil {
+			if _, err := s.repo.DeleteOps(ctx, ids); err != nil {
+				return applied, fmt.Errorf("failed to update outbox: %w", err)
+			}
+			applied += len(batch)
+			continue
+		}
+
+		rejected := errors.Is(err, ErrRejected)
+		for _, id := range ids {
+			if err := s.repo.RecordOpFailure(ctx, id, err.Error(), rejected); err != nil {
+				return applied, fmt.Errorf("failed to update outbox: %w", err)
+			}
+		}
+		if !rejected {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// nextBatch returns the changes at the start of ops that are sent together:
+// a run of edits to the same week's reading list, which take one fetch and
+// one update, or else a single change.
+func nextBatch(ops []PendingOp) []PendingOp {
+	n := 1
+	if isWeekEdit(ops[0]) {
+		for n < len(ops) && !ops[n].Failed && isWeekEdit(ops[n]) && ops[n].WeekID == ops[0].WeekID {
+			n++
+		}
+	}
+	return ops[:n]
+}
+
+func isWeekEdit(op PendingOp) bool {
+	switch op.Kind {
+	case OpAddToWeek, OpRemoveFromWeek, OpMoveInWeek:
+		return true
+	}
+	return false
+}
+
+// apply sends a batch from nextBatch to Notion.
+func (s *Service) apply(ctx context.Context, batch []PendingOp) error {
+	op := batch[0]
+	switch op.Kind {
+	case OpMarkDone:
+		return s.notion.MarkDone(ctx, op.ArticleID)
+	case OpSetTags:
+		return s.notion.SetTags(ctx, op.ArticleID, op.Tags)
+	case OpAddToWeek, OpRemoveFromWeek, OpMoveInWeek:
+		// Edit the list as it is now, so changes made elsewhere in the
+		// meantime are kept.
+		week, err := s.notion.FetchWeek(ctx, op.WeekID)
+		if err != nil {
+			return err
+		}
+		ids, changed := week.ReadingListIDs, false
+		for _, op := range batch {
+			var edited bool
+			if op.Kind == OpMoveInWeek {
+				ids, edited = moveInReadingList(ids, op.ArticleID, op.Position)
+			} else {
+				ids, edited = editReadingList(ids, op.ArticleID, op.Kind == OpAddToWeek)
+			}
+			changed = changed || edited
+		}
+		if !changed {
+			return nil
+		}
+		return s.notion.UpdateWeekReadingList(ctx, week.ID, ids)
+	default:
+		return fmt.Errorf("%w: unknown change %q", ErrRejected, op.Kind)
+	}
+}
+
+// editReadingList adds or removes id, reporting whether the list changed.
+func editReadingList(ids []string, id string, add bool) ([]string, bool) {
+	result := make([]string, 0, len(ids)+1)
+	found := false
+	for _, existing := range ids {
+		if existing == id {
+			found = true
+			if !add {
+				continue
+			}
+		}
+		result = append(result, existing)
+	}
+
+	if add && !found {
+		result = append(result, id)
+	}
+	return result, add != found
+}
+
+// moveInReadingList moves id to position, clamped to the list, reporting
+// whether the order changed. A missing id leaves the list as it is.
+func moveInReadingList(ids []string, id string, position int) ([]string, bool) {
+	from := slices.Index(ids, id)
+	if from < 0 {
+		return ids, false
+	}
+	position = max(0, min(position, len(ids)-1))
+	if position == from {
+		return ids, false
+	}
+
+	result := slices.Delete(slices.Clone(ids), from, from+1)
+	return slices.Insert(result, position, id), true
+}
+
+// enqueue records changes already applied locally. They are pushed by the
+// next Sync, which holds the sync lock, so edits never wait on Notion.
+func (s *Service) enqueue(ctx context.Context, ops ...PendingOp) error {
+	for _, op := range ops {
+		op.CreatedAt = time.Now()
+		if err := s.repo.Enqueue(ctx, op); err != nil {
+			return fmt.Errorf("failed to queue change: %w", err)
+		}
+	}
+	return nil
+}
+
+// Outbox returns the changes not yet in Notion, oldest first.
+func (s *Service) Outbox(ctx context.Context) ([]PendingOp, error) {
+	ops, err := s.repo.PendingOps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	return ops, nil
+}
+
+// Discard drops queued changes without sending them and returns how many
+// were dropped. The local cache keeps them until the next full sync.
+func (s *Service) Discard(ctx context.Context, ids []int64) (int, error) {
+	n, err := s.repo.DeleteOps(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to discard changes: %w", err)
+	}
+	return n, nil
+}
+
+// overlayPending applies queued changes to freshly fetched articles, so a
+// sync does not undo edits that have not reached Notion yet.
+func overlayPending(articles []Article, ops []PendingOp) []Article {
+	done := make(map[string]bool)
+	tags := make(map[string][]string)
+	for _, op := range ops {
+		if op.Failed {
+			continue
+		}
+		switch op.Kind {
+		case OpMarkDone:
+			done[op.ArticleID] = true
+		case OpSetTags:
+			tags[op.ArticleID] = op.Tags
+		}
+	}
+
+	for i := range articles {
+		if done[articles[i].ID] {
+			articles[i].Done = true
+		}
+		if t, ok := tags[articles[i].ID]; ok {
+			articles[i].Tags = t
+		}
+	}
+	return articles
+}
+
+// overlayWeek applies queued edits of week's reading list.
+func overlayWeek(week *Week, ops []PendingOp) {
+	for _, op := range ops {
+		if op.Failed || op.WeekID != week.ID {
+			continue
+		}
+		switch op.Kind {
+		case OpAddToWeek, OpRemoveFromWeek:
+			week.ReadingListIDs, _ = editReadingList(week.ReadingListIDs, op.ArticleID, op.Kind == OpAddToWeek)
+		case OpMoveInWeek:
+			week.ReadingListIDs, _ = moveInReadingList(week.ReadingListIDs, op.ArticleID, op.Position)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package readings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// DefaultReadingMinutes is assumed for articles without a reading time when
+// planning against a budget.
+const DefaultReadingMinutes = 10
+
+// PlanOptions limits the reading list a plan proposes. Articles already on
+// the list count towards every limit.
+type PlanOptions struct {
+	Count      int            // Articles on the list; 0 for no limit
+	Budget     int            // Total reading minutes; 0 for no limit
+	Quotas     map[string]int // Articles wanted per tag; no more are picked
+	AvoidWeeks int            // Skip articles planned in this many weeks before
+	Tags       TagFilter      // Only propose matching articles
+}
+
+// Plan is a proposed reading list for a week.
+type Plan struct {
+	Week     Week      // The week as it is now
+	Planned  []Article // Cached articles already on the list, in order
+	Proposed []Article // Articles to add, in the order they were picked
+	Minutes  int       // Estimated reading time of Planned and Proposed
+}
+
+// ProposePlan picks cached articles for the week containing t in the order
+// of the service's strategy. Tag quotas are filled first, then articles
+// without a quota tag until the count or budget is reached.
+// Articles on the reading list of the AvoidWeeks weeks before are skipped.
+// The proposed articles are recorded as shown in the history.
+func (s *Service) ProposePlan(ctx context.Context, t time.Time, opts PlanOptions) (Plan, error) {
+	if opts.Count <= 0 && opts.Budget <= 0 {
+		return Plan{}, errors.New("a plan needs a count or a reading time budget")
+	}
+
+	week, err := s.WeekAt(ctx, t)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	avoid := make(map[string]bool)
+	for _, id := range week.ReadingListIDs {
+		avoid[id] = true
+	}
+	if opts.AvoidWeeks > 0 {
+		before := week.Start.Add(-time.Second)
+		recent, err := s.ListWeeks(ctx, week.Start.AddDate(0, 0, -7*opts.AvoidWeeks), before)
+		if err != nil {
+			return Plan{}, fmt.Errorf("failed to read recent weeks: %w", err)
+		}
+		for _, w := range recent {
+			for _, id := range w.ReadingListIDs {
+				avoid[id] = true
+			}
+		}
+	}
+
+	all, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return Plan{}, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	plan := Plan{Week: week}
+	byID := make(map[string]Article, len(all))
+	var candidates []Article
+	for _, a := range all {
+		byID[a.ID] = a
+		if !avoid[a.ID] && opts.Tags.Matches(a) {
+			candidates = append(candidates, a)
+		}
+	}
+	for _, id := range week.ReadingListIDs {
+		if a, ok := byID[id]; ok {
+			plan.Planned = append(plan.Planned, a)
+			plan.Minutes += estimatedMinutes(a)
+		}
+	}
+	s.Order(candidates)
+
+	count := len(week.ReadingListIDs)
+	picked := make(map[string]bool)
+	pick := func(a Article) bool {
+		minutes := estimatedMinutes(a)
+		if picked[a.ID] || (opts.Budget > 0 && plan.Minutes+minutes > opts.Budget) {
+			return false
+		}
+		picked[a.ID] = true
+		plan.Proposed = append(plan.Proposed, a)
+		plan.Minutes += minutes
+		count++
+		return true
+	}
+	full := func() bool { return opts.Count > 0 && count >= opts.Count }
+
+	tags := make([]string, 0, len(opts.Quotas))
+	for tag := range opts.Quotas {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		have := 0
+		for _, list := range [][]Article{plan.Planned, plan.Proposed} {
+			for _, a := range list {
+				if hasTag(a, tag) {
+					have++
+				}
+			}
+		}
+		for _, a := range candidates {
+			if have >= opts.Quotas[tag] || full() {
+				break
+			}
+			if hasTag(a, tag) && pick(a) {
+				have++
+			}
+		}
+	}
+
+	for _, a := range candidates {
+		if full() {
+			break
+		}
+		if !slices.ContainsFunc(tags, func(tag string) bool { return hasTag(a, tag) }) {
+			pick(a)
+		}
+	}
+
+	if err := s.record(ctx, EventShown, "", articleIDs(plan.Proposed)...); err != nil {
+		return Plan{}, err
+	}
+	return plan, nil
+}
+
+// ApplyPlan adds the proposed articles to the week's reading list and queues
+// the change for Notion, where it is written with a single update. The added
+// articles are recorded in the history.
+func (s *Service) ApplyPlan(ctx context.Context, plan Plan) error {
+	if len(plan.Proposed) == 0 {
+		return nil
+	}
+	week, err := s.loadWeekAt(ctx, plan.Week.Start)
+	if err != nil {
+		return err
+	}
+
+	s.weeksMu.Lock()
+	defer s.weeksMu.Unlock()
+
+	var ops []PendingOp
+	var added []string
+	for _, a := range plan.Proposed {
+		ids, ok := editReadingList(week.ReadingListIDs, a.ID, true)
+		if !ok {
+			continue
+		}
+		week.ReadingListIDs = ids
+		ops = append(ops, PendingOp{Kind: OpAddToWeek, ArticleID: a.ID, WeekID: week.ID})
+		added = append(added, a.ID)
+	}
+	if err := s.repo.SaveWeek(ctx, *week); err != nil {
+		return fmt.Errorf("failed to save week: %w", err)
+	}
+
+	if err := s.enqueue(ctx, ops...); err != nil {
+		return err
+	}
+	return s.record(ctx, EventPlanned, week.ID, added...)
+}
+
+func estimatedMinutes(a Article) int {
+	if a.ReadingMinutes > 0 {
+		return a.ReadingMinutes
+	}
+	return DefaultReadingMinutes
+}
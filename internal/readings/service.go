@@ -2,103 +2,593 @@ package readings
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // NotionClient defines the interface for fetching articles from Notion.
 type NotionClient interface {
-	FetchArticles(ctx context.Context) ([]Article, error)
-	FetchCurrentWeek(ctx context.Context) (*Week, error)
+	// DatabaseID identifies the reading database the articles come from.
+	DatabaseID() string
+	// FetchArticles returns the articles edited at or after since, including
+	// those marked Done. When since is zero it returns every article that is
+	// not done.
+	FetchArticles(ctx context.Context, since time.Time) ([]Article, error)
+	// DoneArticleIDs returns the IDs of the articles marked Done that are
+	// not in the trash.
+	DoneArticleIDs(ctx context.Context) ([]string, error)
+	// FetchWeekAt returns the week whose span contains t.
+	FetchWeekAt(ctx context.Context, t time.Time) (*Week, error)
+	// ListWeeks returns the weeks overlapping from through to, earliest first.
+	ListWeeks(ctx context.Context, from, to time.Time) ([]Week, error)
+	// FetchWeek returns the week page with the given ID.
+	FetchWeek(ctx context.Context, weekPageID string) (*Week, error)
 	UpdateWeekReadingList(ctx context.Context, weekPageID string, readingPageIDs []string) error
+	// MarkDone ticks the Done checkbox of an article page.
+	MarkDone(ctx context.Context, articleID string) error
+	// SetTags replaces the tags of an article page.
+	SetTags(ctx context.Context, articleID string, tags []string) error
+	// CreateArticle adds a page with the article's title, URL and tags to
+	// the reading database and returns it as stored.
+	CreateArticle(ctx context.Context, article Article) (Article, error)
 }
 
 type Service struct {
-	repo        Repository
-	notion      NotionClient
-	currentWeek *Week
+	repo   Repository
+	notion NotionClient
+	weeks  []*Week // Loaded so far, see loadWeekAt
+
+	// weeksMu guards weeks and serializes edits to them, from reading a
+	// reading list to queuing the change, so they reach the outbox in
+	// order. It is never held while waiting on Notion.
+	weeksMu sync.Mutex
+
+	strategy Strategy
+	rngMu    sync.Mutex // The TUI picks from several goroutines
+	rng      *rand.Rand
 }
 
-func NewService(repo Repository, notion NotionClient) *Service {
-	return &Service{
-		repo:   repo,
-		notion: notion,
+// Option configures a Service.
+type Option func(*Service)
+
+// WithStrategy sets how articles are picked. The default is Uniform.
+func WithStrategy(strategy Strategy) Option {
+	return func(s *Service) {
+		s.strategy = strategy
 	}
 }
 
-func (s *Service) GetReadings(ctx context.Context, count int, tag string) ([]Article, error) {
-	articles, err := s.repo.GetRandom(ctx, count, tag)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get readings: %w", err)
+// WithSeed makes picks repeatable by seeding the random source.
+func WithSeed(seed int64) Option {
+	return func(s *Service) {
+		s.rng = rand.New(rand.NewSource(seed))
 	}
+}
 
-	// If we have enough articles, return them
-	if len(articles) >= count {
-		return articles, nil
+func NewService(repo Repository, notion NotionClient, opts ...Option) *Service {
+	s := &Service{
+		repo:     repo,
+		notion:   notion,
+		strategy: Uniform{},
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Order sorts articles in place with the service's strategy, most wanted
+// first.
+func (s *Service) Order(articles []Article) {
+	s.rngMu.Lock()
+	defer s.rngMu.Unlock()
+	s.strategy.Order(articles, s.rng, time.Now())
+}
+
+// GetReadings picks count articles matching the filter with the service's
+// strategy, syncing first if none are cached.
+func (s *Service) GetReadings(ctx context.Context, count int, filter TagFilter) ([]Article, error) {
+	articles, err := s.repo.GetMatching(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get readings: %w", err)
 	}
 
-	// If we don't have enough (or zero), and it's a fresh start, we might want to sync.
-	// For now, if we have 0, we sync.
 	if len(articles) == 0 {
-		// Try to sync
-		if err := s.Sync(ctx); err != nil {
+		if _, err := s.Sync(ctx); err != nil {
 			return nil, fmt.Errorf("failed to sync: %w", err)
 		}
-		// Try again
-		return s.repo.GetRandom(ctx, count, tag)
+		articles, err = s.repo.GetMatching(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get readings: %w", err)
+		}
 	}
 
+	s.Order(articles)
+	if len(articles) > count {
+		articles = articles[:count]
+	}
+	if err := s.record(ctx, EventShown, "", articleIDs(articles)...); err != nil {
+		return nil, err
+	}
 	return articles, nil
 }
 
-func (s *Service) Sync(ctx context.Context) error {
-	articles, err := s.notion.FetchArticles(ctx)
+// SyncResult counts what a sync changed.
+type SyncResult struct {
+	Pushed  int `json:"pushed"`  // Queued changes sent to Notion
+	Fetched int `json:"fetched"` // Articles saved to the cache
+	Removed int `json:"removed"` // Articles Notion reports done or gone
+
+	// PushError says why queued changes could not all be pushed. They stay
+	// queued for the next sync, which does not stop this one.
+	PushError string `json:"push_error,omitempty"`
+}
+
+// Sync fetches the articles edited since the last successful sync, saves them
+// to the cache and removes those marked Done. The first sync of a database,
+// or the first after its cursor was reset, is a full sync.
+func (s *Service) Sync(ctx context.Context) (SyncResult, error) {
+	return s.sync(ctx, false)
+}
+
+// FullSync refetches every article, ignoring the stored sync cursor, and
+// removes cached articles that Notion no longer returns.
+func (s *Service) FullSync(ctx context.Context) (SyncResult, error) {
+	return s.sync(ctx, true)
+}
+
+func (s *Service) sync(ctx context.Context, full bool) (SyncResult, error) {
+	var result SyncResult
+	databaseID := s.notion.DatabaseID()
+
+	// Push local changes first so the fetch below already reflects them.
+	// Changes still queued are applied over the fetched articles instead.
+	pushed, err := s.Flush(ctx)
+	result.Pushed = pushed
 	if err != nil {
-		return err
+		result.PushError = fmt.Sprintf("failed to push queued changes: %v", err)
 	}
 
-	if err := s.repo.SaveUpsert(ctx, articles); err != nil {
-		return err
+	var since time.Time
+	if !full {
+		cursor, err := s.repo.GetSyncCursor(ctx, databaseID)
+		if err != nil {
+			return result, fmt.Errorf("failed to read sync cursor: %w", err)
+		}
+		since = cursor
+		// Without a cursor only articles that are not done are fetched, so
+		// those marked Done since the cache was filled are found as missing.
+		full = since.IsZero()
 	}
 
-	return nil
+	// Take the new cursor before fetching so pages edited while the sync is
+	// running are picked up again next time.
+	startedAt := time.Now()
+
+	articles, err := s.notion.FetchArticles(ctx, since)
+	if err != nil {
+		return result, err
+	}
+
+	pending, err := s.repo.PendingOps(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	articles = overlayPending(articles, pending)
+
+	var active []Article
+	var done, missing []string
+	for _, a := range articles {
+		if a.Done {
+			done = append(done, a.ID)
+		} else {
+			active = append(active, a)
+		}
+	}
+
+	if full {
+		done, missing, err = s.missingFrom(ctx, active)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if err := s.repo.SaveUpsert(ctx, active); err != nil {
+		return result, err
+	}
+	result.Fetched = len(active)
+
+	if len(done) > 0 {
+		if err := s.repo.MarkRemoved(ctx, done, RemovedDone); err != nil {
+			return result, fmt.Errorf("failed to remove articles: %w", err)
+		}
+	}
+	if len(missing) > 0 {
+		if err := s.repo.MarkRemoved(ctx, missing, RemovedMissing); err != nil {
+			return result, fmt.Errorf("failed to remove articles: %w", err)
+		}
+	}
+	result.Removed = len(done) + len(missing)
+
+	if err := s.repo.SetSyncCursor(ctx, databaseID, startedAt); err != nil {
+		return result, fmt.Errorf("failed to save sync cursor: %w", err)
+	}
+
+	return result, nil
+}
+
+// missingFrom returns the IDs of cached articles that are not in fetched,
+// split into those marked Done and those gone from the reading database. A
+// full fetch leaves out both, so when articles are missing one query for
+// the done articles tells them apart.
+func (s *Service) missingFrom(ctx context.Context, fetched []Article) (done, missing []string, err error) {
+	cached, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	seen := make(map[string]bool, len(fetched))
+	for _, a := range fetched {
+		seen[a.ID] = true
+	}
+
+	var left []string
+	for _, a := range cached {
+		if !seen[a.ID] {
+			left = append(left, a.ID)
+		}
+	}
+	if len(left) == 0 {
+		return nil, nil, nil
+	}
+
+	doneIDs, err := s.notion.DoneArticleIDs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, id := range left {
+		if slices.Contains(doneIDs, id) {
+			done = append(done, id)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return done, missing, nil
 }
 
 func (s *Service) GetAll(ctx context.Context) ([]Article, error) {
 	return s.repo.GetAll(ctx)
 }
 
-func (s *Service) ToggleReadingInCurrentWeek(ctx context.Context, articleID string) (bool, error) {
-	if s.currentWeek == nil {
-		week, err := s.notion.FetchCurrentWeek(ctx)
-		if err != nil {
-			return false, err
+// ListOptions selects cached articles for non-interactive output.
+type ListOptions struct {
+	Tags   TagFilter
+	Limit  int  // At most this many articles; 0 means all
+	Random bool // Order with the service's strategy instead of by title
+}
+
+// List returns cached articles without syncing, for scripting.
+func (s *Service) List(ctx context.Context, opts ListOptions) ([]Article, error) {
+	all, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list articles: %w", err)
+	}
+
+	articles := make([]Article, 0, len(all))
+	for _, a := range all {
+		if opts.Tags.Matches(a) {
+			articles = append(articles, a)
 		}
-		s.currentWeek = week
 	}
 
-	// Check if article is already in the list
-	exists := false
-	var newIDs []string
-	for _, id := range s.currentWeek.ReadingListIDs {
-		if id == articleID {
-			exists = true
-		} else {
-			newIDs = append(newIDs, id)
+	if opts.Random {
+		s.Order(articles)
+	} else {
+		sort.SliceStable(articles, func(i, j int) bool {
+			return strings.ToLower(articles[i].Title) < strings.ToLower(articles[j].Title)
+		})
+	}
+
+	if opts.Limit > 0 && len(articles) > opts.Limit {
+		articles = articles[:opts.Limit]
+		// A short picked list is a suggestion; a full listing is not.
+		if opts.Random {
+			if err := s.record(ctx, EventShown, "", articleIDs(articles)...); err != nil {
+				return nil, err
+			}
 		}
 	}
+	return articles, nil
+}
+
+// Search finds cached articles by title, URL and tags, best matches first.
+func (s *Service) Search(ctx context.Context, query string, limit int) ([]Article, error) {
+	articles, err := s.repo.Search(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search articles: %w", err)
+	}
+	return articles, nil
+}
+
+// TagCounts returns how many cached articles carry each tag.
+func (s *Service) TagCounts(ctx context.Context) ([]TagCount, error) {
+	return s.repo.TagCounts(ctx)
+}
 
-	added := false
-	if !exists {
-		newIDs = append(newIDs, articleID)
-		added = true
+// Find looks up a cached article by ID or URL.
+func (s *Service) Find(ctx context.Context, ref string) (*Article, error) {
+	return s.repo.Find(ctx, ref)
+}
+
+// Add creates the article in Notion and saves it to the cache. Unlike other
+// edits it is not queued, since the page ID is only known once Notion has
+// created it. It returns ErrDuplicate if the URL is already cached.
+func (s *Service) Add(ctx context.Context, article Article) (Article, error) {
+	existing, err := s.repo.Find(ctx, article.URL)
+	switch {
+	case err == nil:
+		return *existing, fmt.Errorf("%w: %s", ErrDuplicate, existing.Title)
+	case !errors.Is(err, ErrNotFound):
+		return Article{}, fmt.Errorf("failed to read cache: %w", err)
 	}
 
-	if err := s.notion.UpdateWeekReadingList(ctx, s.currentWeek.ID, newIDs); err != nil {
+	created, err := s.notion.CreateArticle(ctx, article)
+	if err != nil {
+		return Article{}, err
+	}
+	if err := s.repo.SaveUpsert(ctx, []Article{created}); err != nil {
+		return Article{}, fmt.Errorf("failed to save article: %w", err)
+	}
+	return created, nil
+}
+
+// MarkDone removes the article from the cache, queues marking it as done in
+// Notion and records it in the history.
+func (s *Service) MarkDone(ctx context.Context, articleID string) error {
+	if err := s.repo.MarkRemoved(ctx, []string{articleID}, RemovedDone); err != nil {
+		return fmt.Errorf("failed to remove article from cache: %w", err)
+	}
+
+	if err := s.enqueue(ctx, PendingOp{Kind: OpMarkDone, ArticleID: articleID}); err != nil {
+		return err
+	}
+	return s.record(ctx, EventDone, "", articleID)
+}
+
+// SetTags replaces the article's tags in the cache and queues the change
+// for Notion.
+func (s *Service) SetTags(ctx context.Context, articleID string, tags []string) error {
+	article, err := s.repo.Find(ctx, articleID)
+	if err != nil {
+		return err
+	}
+
+	article.Tags = tags
+	if err := s.repo.SaveUpsert(ctx, []Article{*article}); err != nil {
+		return fmt.Errorf("failed to save tags: %w", err)
+	}
+
+	return s.enqueue(ctx, PendingOp{Kind: OpSetTags, ArticleID: article.ID, Tags: tags})
+}
+
+// loadWeekAt fetches the week containing t once and keeps it for later
+// calls. When Notion cannot be reached it falls back to the local copy.
+// weeksMu is only held while the loaded weeks are read or added to, not
+// while waiting on Notion; callers lock it to read or edit the week.
+func (s *Service) loadWeekAt(ctx context.Context, t time.Time) (*Week, error) {
+	if week := s.loadedWeek(t); week != nil {
+		return week, nil
+	}
+
+	week, err := s.notion.FetchWeekAt(ctx, t)
+	if err != nil {
+		cached, cacheErr := s.repo.WeekAt(ctx, t)
+		if cacheErr != nil {
+			return nil, err
+		}
+		week = cached
+	}
+
+	s.weeksMu.Lock()
+	defer s.weeksMu.Unlock()
+	// Another call may have loaded it meanwhile, and edited it since.
+	for _, loaded := range s.weeks {
+		if loaded.ID == week.ID {
+			return loaded, nil
+		}
+	}
+
+	pending, err := s.repo.PendingOps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	overlayWeek(week, pending)
+
+	if err := s.repo.SaveWeek(ctx, *week); err != nil {
+		return nil, fmt.Errorf("failed to save week: %w", err)
+	}
+
+	s.weeks = append(s.weeks, week)
+	return week, nil
+}
+
+// loadedWeek returns the loaded week containing t, or nil.
+func (s *Service) loadedWeek(t time.Time) *Week {
+	s.weeksMu.Lock()
+	defer s.weeksMu.Unlock()
+	for _, week := range s.weeks {
+		if week.Contains(t) {
+			return week
+		}
+	}
+	return nil
+}
+
+// WeekAt returns the week containing t with its reading list in order.
+func (s *Service) WeekAt(ctx context.Context, t time.Time) (Week, error) {
+	week, err := s.loadWeekAt(ctx, t)
+	if err != nil {
+		return Week{}, err
+	}
+
+	s.weeksMu.Lock()
+	defer s.weeksMu.Unlock()
+	result := *week
+	result.ReadingListIDs = slices.Clone(week.ReadingListIDs)
+	return result, nil
+}
+
+// CurrentWeek returns the current week with its reading list in order.
+func (s *Service) CurrentWeek(ctx context.Context) (Week, error) {
+	return s.WeekAt(ctx, time.Now())
+}
+
+// CurrentWeekReadingList returns the IDs of the articles planned for the
+// current week.
+func (s *Service) CurrentWeekReadingList(ctx context.Context) ([]string, error) {
+	week, err := s.WeekAt(ctx, time.Now())
+	return week.ReadingListIDs, err
+}
+
+// ListWeeks returns the weeks in Notion overlapping from through to,
+// earliest first, or the local copies of those seen before when Notion
+// cannot be reached. Reading lists include changes still waiting in the
+// outbox.
+func (s *Service) ListWeeks(ctx context.Context, from, to time.Time) ([]Week, error) {
+	weeks, err := s.notion.ListWeeks(ctx, from, to)
+	if err != nil {
+		cached, cacheErr := s.repo.Weeks(ctx, from, to)
+		if cacheErr != nil || len(cached) == 0 {
+			return nil, fmt.Errorf("failed to list weeks: %w", err)
+		}
+		weeks = cached
+	}
+
+	pending, err := s.repo.PendingOps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	for i := range weeks {
+		overlayWeek(&weeks[i], pending)
+	}
+	return weeks, nil
+}
+
+// ToggleReadingInCurrentWeek adds the article to the current week's reading
+// list, or removes it if it is already there. See ToggleReadingInWeekAt.
+func (s *Service) ToggleReadingInCurrentWeek(ctx context.Context, articleID string) (bool, error) {
+	return s.ToggleReadingInWeekAt(ctx, time.Now(), articleID)
+}
+
+// ToggleReadingInWeekAt adds the article to the reading list of the week
+// containing t, or removes it if it is already there, and queues the change
+// for Notion. It reports whether the article was added; additions are
+// recorded in the history.
+func (s *Service) ToggleReadingInWeekAt(ctx context.Context, t time.Time, articleID string) (bool, error) {
+	week, err := s.loadWeekAt(ctx, t)
+	if err != nil {
 		return false, err
 	}
 
+	s.weeksMu.Lock()
+	defer s.weeksMu.Unlock()
+
+	added := !slices.Contains(week.ReadingListIDs, articleID)
+	ids, _ := editReadingList(week.ReadingListIDs, articleID, added)
+	kind := OpRemoveFromWeek
+	if added {
+		kind = OpAddToWeek
+	}
+
 	// Update cache
-	s.currentWeek.ReadingListIDs = newIDs
+	week.ReadingListIDs = ids
+	if err := s.repo.SaveWeek(ctx, *week); err != nil {
+		return false, fmt.Errorf("failed to save week: %w", err)
+	}
 
+	if err := s.enqueue(ctx, PendingOp{Kind: kind, ArticleID: articleID, WeekID: week.ID}); err != nil {
+		return false, err
+	}
+	if added {
+		if err := s.record(ctx, EventPlanned, week.ID, articleID); err != nil {
+			return false, err
+		}
+	}
 	return added, nil
 }
+
+// MoveInWeekAt moves an article to position in the reading list of the week
+// containing t and queues the new order for Notion.
+func (s *Service) MoveInWeekAt(ctx context.Context, t time.Time, articleID string, position int) error {
+	week, err := s.loadWeekAt(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	s.weeksMu.Lock()
+	defer s.weeksMu.Unlock()
+
+	ids, changed := moveInReadingList(week.ReadingListIDs, articleID, position)
+	if !changed {
+		return nil
+	}
+
+	week.ReadingListIDs = ids
+	if err := s.repo.SaveWeek(ctx, *week); err != nil {
+		return fmt.Errorf("failed to save week: %w", err)
+	}
+
+	return s.enqueue(ctx, PendingOp{Kind: OpMoveInWeek, ArticleID: articleID, WeekID: week.ID, Position: position})
+}
+
+// ReorderWeekAt puts the reading list of the week containing t in order and
+// queues the moves that get there for Notion. Articles in order that are not
+// on the list are ignored and those missing from order keep their place at
+// the end.
+func (s *Service) ReorderWeekAt(ctx context.Context, t time.Time, order []string) error {
+	week, err := s.loadWeekAt(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	s.weeksMu.Lock()
+	defer s.weeksMu.Unlock()
+
+	target := make([]string, 0, len(week.ReadingListIDs))
+	for _, id := range order {
+		if slices.Contains(week.ReadingListIDs, id) && !slices.Contains(target, id) {
+			target = append(target, id)
+		}
+	}
+	for _, id := range week.ReadingListIDs {
+		if !slices.Contains(target, id) {
+			target = append(target, id)
+		}
+	}
+
+	ids := week.ReadingListIDs
+	var ops []PendingOp
+	for position, id := range target {
+		var changed bool
+		if ids, changed = moveInReadingList(ids, id, position); changed {
+			ops = append(ops, PendingOp{Kind: OpMoveInWeek, ArticleID: id, WeekID: week.ID, Position: position})
+		}
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	week.ReadingListIDs = ids
+	if err := s.repo.SaveWeek(ctx, *week); err != nil {
+		return fmt.Errorf("failed to save week: %w", err)
+	}
+
+	return s.enqueue(ctx, ops...)
+}
@@ -2,16 +2,28 @@ package readings_test
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"productivity.go/internal/readings"
 )
 
-// MockRepository is a mock implementation of readings.Repository
+// MockRepository is a mock implementation of readings.Repository. The
+// outbox, weeks and history are kept in memory instead of being mocked,
+// since the service reads back what it writes.
 type MockRepository struct {
 	mock.Mock
+	ops    []readings.PendingOp
+	nextID int64
+	weeks  []readings.Week
+	events []readings.Event
 }
 
 func (m *MockRepository) SaveUpsert(ctx context.Context, articles []readings.Article) error {
@@ -19,8 +31,8 @@ func (m *MockRepository) SaveUpsert(ctx context.Context, articles []readings.Art
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetRandom(ctx context.Context, count int, tag string) ([]readings.Article, error) {
-	args := m.Called(ctx, count, tag)
+func (m *MockRepository) GetMatching(ctx context.Context, filter readings.TagFilter) ([]readings.Article, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]readings.Article), args.Error(1)
 }
 
@@ -29,6 +41,134 @@ func (m *MockRepository) GetAll(ctx context.Context) ([]readings.Article, error)
 	return args.Get(0).([]readings.Article), args.Error(1)
 }
 
+func (m *MockRepository) Search(ctx context.Context, query string, limit int) ([]readings.Article, error) {
+	args := m.Called(ctx, query, limit)
+	return args.Get(0).([]readings.Article), args.Error(1)
+}
+
+func (m *MockRepository) TagCounts(ctx context.Context) ([]readings.TagCount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]readings.TagCount), args.Error(1)
+}
+
+func (m *MockRepository) Find(ctx context.Context, ref string) (*readings.Article, error) {
+	args := m.Called(ctx, ref)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*readings.Article), args.Error(1)
+}
+
+func (m *MockRepository) RecordEvents(ctx context.Context, events []readings.Event) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *MockRepository) History(ctx context.Context, filter readings.HistoryFilter) ([]readings.Event, error) {
+	return append([]readings.Event(nil), m.events...), nil
+}
+
+// recorded returns the IDs of the articles with events of the given kind,
+// oldest first.
+func (m *MockRepository) recorded(kind readings.EventKind) []string {
+	var ids []string
+	for _, e := range m.events {
+		if e.Kind == kind {
+			ids = append(ids, e.ArticleID)
+		}
+	}
+	return ids
+}
+
+func (m *MockRepository) Removed(ctx context.Context) ([]readings.Removal, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]readings.Removal), args.Error(1)
+}
+
+func (m *MockRepository) MarkRemoved(ctx context.Context, ids []string, reason readings.RemovalReason) error {
+	args := m.Called(ctx, ids, reason)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetSyncCursor(ctx context.Context, databaseID string) (time.Time, error) {
+	args := m.Called(ctx, databaseID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockRepository) SetSyncCursor(ctx context.Context, databaseID string, syncedAt time.Time) error {
+	args := m.Called(ctx, databaseID, syncedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveWeek(ctx context.Context, week readings.Week) error {
+	week.ReadingListIDs = append([]string(nil), week.ReadingListIDs...)
+	for i, w := range m.weeks {
+		if w.ID == week.ID {
+			m.weeks[i] = week
+			return nil
+		}
+	}
+	m.weeks = append(m.weeks, week)
+	return nil
+}
+
+func (m *MockRepository) WeekAt(ctx context.Context, t time.Time) (*readings.Week, error) {
+	for _, w := range m.weeks {
+		if w.Contains(t) {
+			return &w, nil
+		}
+	}
+	return nil, readings.ErrNotFound
+}
+
+func (m *MockRepository) Weeks(ctx context.Context, from, to time.Time) ([]readings.Week, error) {
+	var weeks []readings.Week
+	for _, w := range m.weeks {
+		if !w.End.Before(from) && !w.Start.After(to) {
+			weeks = append(weeks, w)
+		}
+	}
+	return weeks, nil
+}
+
+func (m *MockRepository) Enqueue(ctx context.Context, op readings.PendingOp) error {
+	m.nextID++
+	op.ID = m.nextID
+	m.ops = append(m.ops, op)
+	return nil
+}
+
+func (m *MockRepository) PendingOps(ctx context.Context) ([]readings.PendingOp, error) {
+	return append([]readings.PendingOp(nil), m.ops...), nil
+}
+
+func (m *MockRepository) RecordOpFailure(ctx context.Context, id int64, message string, failed bool) error {
+	for i := range m.ops {
+		if m.ops[i].ID == id {
+			m.ops[i].Attempts++
+			m.ops[i].LastError = message
+			m.ops[i].Failed = failed
+		}
+	}
+	return nil
+}
+
+func (m *MockRepository) DeleteOps(ctx context.Context, ids []int64) (int, error) {
+	var kept []readings.PendingOp
+	for _, op := range m.ops {
+		drop := false
+		for _, id := range ids {
+			drop = drop || op.ID == id
+		}
+		if !drop {
+			kept = append(kept, op)
+		}
+	}
+	n := len(m.ops) - len(kept)
+	m.ops = kept
+	return n, nil
+}
+
 func (m *MockRepository) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -39,24 +179,62 @@ type MockNotionClient struct {
 	mock.Mock
 }
 
-func (m *MockNotionClient) FetchArticles(ctx context.Context) ([]readings.Article, error) {
-	args := m.Called(ctx)
+func (m *MockNotionClient) DatabaseID() string {
+	return "reading-db"
+}
+
+func (m *MockNotionClient) FetchArticles(ctx context.Context, since time.Time) ([]readings.Article, error) {
+	args := m.Called(ctx, since)
 	return args.Get(0).([]readings.Article), args.Error(1)
 }
 
-func (m *MockNotionClient) FetchCurrentWeek(ctx context.Context) (*readings.Week, error) {
+func (m *MockNotionClient) DoneArticleIDs(ctx context.Context) ([]string, error) {
 	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockNotionClient) FetchWeekAt(ctx context.Context, t time.Time) (*readings.Week, error) {
+	args := m.Called(ctx, t)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*readings.Week), args.Error(1)
 }
 
+func (m *MockNotionClient) ListWeeks(ctx context.Context, from, to time.Time) ([]readings.Week, error) {
+	args := m.Called(ctx, from, to)
+	weeks, _ := args.Get(0).([]readings.Week)
+	return weeks, args.Error(1)
+}
+
+func (m *MockNotionClient) FetchWeek(ctx context.Context, weekPageID string) (*readings.Week, error) {
+	args := m.Called(ctx, weekPageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*readings.Week), args.Error(1)
+}
+
+func (m *MockNotionClient) SetTags(ctx context.Context, articleID string, tags []string) error {
+	args := m.Called(ctx, articleID, tags)
+	return args.Error(0)
+}
+
 func (m *MockNotionClient) UpdateWeekReadingList(ctx context.Context, weekPageID string, readingPageIDs []string) error {
 	args := m.Called(ctx, weekPageID, readingPageIDs)
 	return args.Error(0)
 }
 
+func (m *MockNotionClient) MarkDone(ctx context.Context, articleID string) error {
+	args := m.Called(ctx, articleID)
+	return args.Error(0)
+}
+
+func (m *MockNotionClient) CreateArticle(ctx context.Context, article readings.Article) (readings.Article, error) {
+	args := m.Called(ctx, article)
+	return args.Get(0).(readings.Article), args.Error(1)
+}
+
 func TestGetReadings_CacheHit(t *testing.T) {
 	repo := new(MockRepository)
 	notion := new(MockNotionClient)
@@ -66,12 +244,13 @@ func TestGetReadings_CacheHit(t *testing.T) {
 		{ID: "1", Title: "Test Article", URL: "http://example.com"},
 	}
 
-	repo.On("GetRandom", mock.Anything, 7, "").Return(expectedArticles, nil)
+	repo.On("GetMatching", mock.Anything, readings.TagFilter{}).Return(expectedArticles, nil)
 
-	articles, err := svc.GetReadings(context.Background(), 7, "")
+	articles, err := svc.GetReadings(context.Background(), 7, readings.TagFilter{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedArticles, articles)
+	assert.Equal(t, []string{"1"}, repo.recorded(readings.EventShown))
 	repo.AssertExpectations(t)
 	notion.AssertNotCalled(t, "FetchArticles")
 }
@@ -86,15 +265,18 @@ func TestGetReadings_CacheMiss_TriggersSync(t *testing.T) {
 	}
 
 	// First call returns empty
-	repo.On("GetRandom", mock.Anything, 7, "").Return([]readings.Article{}, nil).Once()
-	// Sync fetches articles
-	notion.On("FetchArticles", mock.Anything).Return(fetchedArticles, nil)
+	repo.On("GetMatching", mock.Anything, readings.TagFilter{}).Return([]readings.Article{}, nil).Once()
+	// Sync fetches everything on the first run
+	repo.On("GetSyncCursor", mock.Anything, "reading-db").Return(time.Time{}, nil)
+	notion.On("FetchArticles", mock.Anything, time.Time{}).Return(fetchedArticles, nil)
+	repo.On("GetAll", mock.Anything).Return([]readings.Article{}, nil)
 	// SaveUpsert is called
 	repo.On("SaveUpsert", mock.Anything, fetchedArticles).Return(nil)
+	repo.On("SetSyncCursor", mock.Anything, "reading-db", mock.AnythingOfType("time.Time")).Return(nil)
 	// Second call returns fetched articles
-	repo.On("GetRandom", mock.Anything, 7, "").Return(fetchedArticles, nil).Once()
+	repo.On("GetMatching", mock.Anything, readings.TagFilter{}).Return(fetchedArticles, nil).Once()
 
-	articles, err := svc.GetReadings(context.Background(), 7, "")
+	articles, err := svc.GetReadings(context.Background(), 7, readings.TagFilter{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, fetchedArticles, articles)
@@ -102,23 +284,179 @@ func TestGetReadings_CacheMiss_TriggersSync(t *testing.T) {
 	notion.AssertExpectations(t)
 }
 
-func TestToggleReadingInCurrentWeek_Add(t *testing.T) {
+func TestSync_FetchesSinceCursor(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	cursor := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	edited := []readings.Article{{ID: "1", Title: "Edited Article"}}
+
+	repo.On("GetSyncCursor", mock.Anything, "reading-db").Return(cursor, nil)
+	notion.On("FetchArticles", mock.Anything, cursor).Return(edited, nil)
+	repo.On("SaveUpsert", mock.Anything, edited).Return(nil)
+	repo.On("SetSyncCursor", mock.Anything, "reading-db", mock.MatchedBy(func(t time.Time) bool {
+		return t.After(cursor)
+	})).Return(nil)
+
+	_, err := svc.Sync(context.Background())
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	notion.AssertExpectations(t)
+}
+
+func TestFullSync_IgnoresCursor(t *testing.T) {
 	repo := new(MockRepository)
 	notion := new(MockNotionClient)
 	svc := readings.NewService(repo, notion)
 
-	week := &readings.Week{
+	all := []readings.Article{{ID: "1"}, {ID: "2"}}
+
+	notion.On("FetchArticles", mock.Anything, time.Time{}).Return(all, nil)
+	repo.On("GetAll", mock.Anything).Return(all, nil)
+	repo.On("SaveUpsert", mock.Anything, all).Return(nil)
+	repo.On("SetSyncCursor", mock.Anything, "reading-db", mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := svc.FullSync(context.Background())
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "GetSyncCursor", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+	notion.AssertExpectations(t)
+}
+
+func TestSync_RemovesDoneArticles(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	cursor := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	edited := []readings.Article{
+		{ID: "1", Title: "Still Reading"},
+		{ID: "2", Title: "Finished", Done: true},
+	}
+
+	repo.On("GetSyncCursor", mock.Anything, "reading-db").Return(cursor, nil)
+	notion.On("FetchArticles", mock.Anything, cursor).Return(edited, nil)
+	repo.On("SaveUpsert", mock.Anything, edited[:1]).Return(nil)
+	repo.On("MarkRemoved", mock.Anything, []string{"2"}, readings.RemovedDone).Return(nil)
+	repo.On("SetSyncCursor", mock.Anything, "reading-db", mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := svc.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, readings.SyncResult{Fetched: 1, Removed: 1}, result)
+	repo.AssertExpectations(t)
+}
+
+func TestFullSync_RemovesMissingArticles(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	fetched := []readings.Article{{ID: "1"}, {ID: "3"}}
+	cached := []readings.Article{{ID: "1"}, {ID: "2"}, {ID: "4"}, {ID: "5"}}
+
+	notion.On("FetchArticles", mock.Anything, time.Time{}).Return(fetched, nil)
+	// One query tells the articles marked Done from those that are gone.
+	notion.On("DoneArticleIDs", mock.Anything).Return([]string{"4", "7"}, nil).Once()
+	repo.On("GetAll", mock.Anything).Return(cached, nil)
+	repo.On("SaveUpsert", mock.Anything, fetched).Return(nil)
+	repo.On("MarkRemoved", mock.Anything, []string{"4"}, readings.RemovedDone).Return(nil)
+	repo.On("MarkRemoved", mock.Anything, []string{"2", "5"}, readings.RemovedMissing).Return(nil)
+	repo.On("SetSyncCursor", mock.Anything, "reading-db", mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := svc.FullSync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, readings.SyncResult{Fetched: 2, Removed: 3}, result)
+	repo.AssertExpectations(t)
+	notion.AssertExpectations(t)
+}
+
+func TestFullSync_LookupErrorKeepsCache(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	notion.On("FetchArticles", mock.Anything, time.Time{}).Return([]readings.Article{{ID: "1"}}, nil)
+	notion.On("DoneArticleIDs", mock.Anything).Return([]string(nil), assert.AnError)
+	repo.On("GetAll", mock.Anything).Return([]readings.Article{{ID: "1"}, {ID: "2"}}, nil)
+
+	_, err := svc.FullSync(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	repo.AssertNotCalled(t, "MarkRemoved", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "SetSyncCursor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSync_WithoutCursorRemovesMissingArticles(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	// "2" was cached before the cursor was reset and has been marked Done
+	// since, so Notion no longer returns it.
+	fetched := []readings.Article{{ID: "1"}}
+	cached := []readings.Article{{ID: "1"}, {ID: "2"}}
+
+	repo.On("GetSyncCursor", mock.Anything, "reading-db").Return(time.Time{}, nil)
+	notion.On("FetchArticles", mock.Anything, time.Time{}).Return(fetched, nil)
+	notion.On("DoneArticleIDs", mock.Anything).Return([]string{"2"}, nil)
+	repo.On("GetAll", mock.Anything).Return(cached, nil)
+	repo.On("SaveUpsert", mock.Anything, fetched).Return(nil)
+	repo.On("MarkRemoved", mock.Anything, []string{"2"}, readings.RemovedDone).Return(nil)
+	repo.On("SetSyncCursor", mock.Anything, "reading-db", mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := svc.Sync(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, readings.SyncResult{Fetched: 1, Removed: 1}, result)
+	repo.AssertExpectations(t)
+}
+
+func TestSync_FetchErrorKeepsCursor(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	repo.On("GetSyncCursor", mock.Anything, "reading-db").Return(time.Time{}, nil)
+	notion.On("FetchArticles", mock.Anything, time.Time{}).Return([]readings.Article(nil), assert.AnError)
+
+	_, err := svc.Sync(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	repo.AssertNotCalled(t, "SetSyncCursor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// thisWeek returns week-1, spanning the days around today.
+func thisWeek(readingList ...string) *readings.Week {
+	now := time.Now()
+	return &readings.Week{
 		ID:             "week-1",
-		ReadingListIDs: []string{"article-1"},
+		Start:          now.AddDate(0, 0, -3),
+		End:            now.AddDate(0, 0, 3),
+		ReadingListIDs: readingList,
 	}
+}
+
+func TestToggleReadingInCurrentWeek_Add(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	week := thisWeek("article-1")
 
-	notion.On("FetchCurrentWeek", mock.Anything).Return(week, nil)
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(week, nil)
+	notion.On("FetchWeek", mock.Anything, "week-1").Return(&readings.Week{ID: "week-1", ReadingListIDs: []string{"article-1"}}, nil)
 	notion.On("UpdateWeekReadingList", mock.Anything, "week-1", []string{"article-1", "article-2"}).Return(nil)
 
 	added, err := svc.ToggleReadingInCurrentWeek(context.Background(), "article-2")
 	assert.NoError(t, err)
 	assert.True(t, added)
+	require.Len(t, repo.ops, 1, "The change is queued, not sent")
+	notion.AssertNotCalled(t, "UpdateWeekReadingList", mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = svc.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, repo.ops)
 	notion.AssertExpectations(t)
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, readings.Event{Kind: readings.EventPlanned, ArticleID: "article-2", WeekID: "week-1", At: repo.events[0].At}, repo.events[0])
 }
 
 func TestToggleReadingInCurrentWeek_Remove(t *testing.T) {
@@ -126,16 +464,639 @@ func TestToggleReadingInCurrentWeek_Remove(t *testing.T) {
 	notion := new(MockNotionClient)
 	svc := readings.NewService(repo, notion)
 
-	week := &readings.Week{
-		ID:             "week-1",
-		ReadingListIDs: []string{"article-1", "article-2"},
-	}
+	week := thisWeek("article-1", "article-2")
 
-	notion.On("FetchCurrentWeek", mock.Anything).Return(week, nil)
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(week, nil)
+	notion.On("FetchWeek", mock.Anything, "week-1").Return(&readings.Week{ID: "week-1", ReadingListIDs: []string{"article-1", "article-2"}}, nil)
 	notion.On("UpdateWeekReadingList", mock.Anything, "week-1", []string{"article-1"}).Return(nil)
 
 	added, err := svc.ToggleReadingInCurrentWeek(context.Background(), "article-2")
 	assert.NoError(t, err)
 	assert.False(t, added)
+
+	_, err = svc.Flush(context.Background())
+	assert.NoError(t, err)
 	notion.AssertExpectations(t)
 }
+
+func TestToggleReadingInCurrentWeek_OfflineQueues(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	// The week was seen before going offline.
+	now := time.Now()
+	repo.weeks = []readings.Week{{ID: "week-1", Start: now.Add(-time.Hour), End: now.Add(time.Hour), ReadingListIDs: []string{"article-1"}}}
+
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+	notion.On("FetchWeek", mock.Anything, "week-1").Return(nil, assert.AnError)
+
+	added, err := svc.ToggleReadingInCurrentWeek(context.Background(), "article-2")
+	assert.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []string{"article-1", "article-2"}, repo.weeks[0].ReadingListIDs)
+
+	_, err = svc.Flush(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	require.Len(t, repo.ops, 1)
+	assert.Equal(t, readings.OpAddToWeek, repo.ops[0].Kind)
+	assert.Equal(t, "week-1", repo.ops[0].WeekID)
+	assert.Equal(t, 1, repo.ops[0].Attempts)
+	assert.False(t, repo.ops[0].Failed)
+	notion.AssertNotCalled(t, "UpdateWeekReadingList", mock.Anything, mock.Anything, mock.Anything)
+
+	// Back online, the next flush applies the change to the list as it is
+	// in Notion by then.
+	notion.ExpectedCalls = nil
+	notion.On("FetchWeek", mock.Anything, "week-1").Return(&readings.Week{ID: "week-1", ReadingListIDs: []string{"article-1", "article-3"}}, nil)
+	notion.On("UpdateWeekReadingList", mock.Anything, "week-1", []string{"article-1", "article-3", "article-2"}).Return(nil)
+
+	applied, err := svc.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	assert.Empty(t, repo.ops)
+	notion.AssertExpectations(t)
+}
+
+func TestFlush_SkipsRejectedAndStopsAtTransientErrors(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	for _, op := range []readings.PendingOp{
+		{Kind: readings.OpMarkDone, ArticleID: "deleted"},
+		{Kind: readings.OpSetTags, ArticleID: "article-1", Tags: []string{"go"}},
+		{Kind: readings.OpMarkDone, ArticleID: "article-2"},
+		{Kind: readings.OpMarkDone, ArticleID: "article-3"},
+	} {
+		require.NoError(t, repo.Enqueue(ctx, op))
+	}
+
+	notion.On("MarkDone", mock.Anything, "deleted").Return(fmt.Errorf("failed to mark article as done: %w", readings.ErrRejected))
+	notion.On("SetTags", mock.Anything, "article-1", []string{"go"}).Return(nil)
+	notion.On("MarkDone", mock.Anything, "article-2").Return(assert.AnError)
+
+	applied, err := svc.Flush(ctx)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, applied)
+	notion.AssertNotCalled(t, "MarkDone", mock.Anything, "article-3")
+
+	require.Len(t, repo.ops, 3)
+	assert.True(t, repo.ops[0].Failed)
+	assert.Contains(t, repo.ops[0].LastError, "rejected by Notion")
+	assert.False(t, repo.ops[1].Failed)
+	assert.Equal(t, 1, repo.ops[1].Attempts)
+	assert.Equal(t, 0, repo.ops[2].Attempts)
+
+	// Rejected changes are not retried.
+	notion.ExpectedCalls = nil
+	notion.On("MarkDone", mock.Anything, "article-2").Return(nil)
+	notion.On("MarkDone", mock.Anything, "article-3").Return(nil)
+
+	applied, err = svc.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	require.Len(t, repo.ops, 1)
+	assert.Equal(t, "deleted", repo.ops[0].ArticleID)
+
+	n, err := svc.Discard(ctx, []int64{repo.ops[0].ID})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Empty(t, repo.ops)
+}
+
+func TestSync_PushesQueuedChangesFirst(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	cursor := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Enqueue(ctx, readings.PendingOp{Kind: readings.OpMarkDone, ArticleID: "article-1"}))
+	notion.On("MarkDone", mock.Anything, "article-1").Return(assert.AnError)
+
+	// A change that cannot be pushed does not hold up the fetch, and stays
+	// applied to what was fetched.
+	edited := []readings.Article{{ID: "article-1"}, {ID: "article-2"}}
+	repo.On("GetSyncCursor", mock.Anything, "reading-db").Return(cursor, nil)
+	notion.On("FetchArticles", mock.Anything, cursor).Return(edited, nil)
+	repo.On("SaveUpsert", mock.Anything, edited[1:]).Return(nil)
+	repo.On("MarkRemoved", mock.Anything, []string{"article-1"}, readings.RemovedDone).Return(nil)
+	repo.On("SetSyncCursor", mock.Anything, "reading-db", mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := svc.Sync(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, result.PushError, assert.AnError.Error())
+	assert.Equal(t, 1, result.Fetched)
+	assert.Len(t, repo.ops, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestSetTags(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	article := &readings.Article{ID: "article-1", Title: "Go", Tags: []string{"go"}}
+	repo.On("Find", mock.Anything, "article-1").Return(article, nil)
+	repo.On("SaveUpsert", mock.Anything, []readings.Article{{ID: "article-1", Title: "Go", Tags: []string{"go", "generics"}}}).Return(nil)
+	notion.On("SetTags", mock.Anything, "article-1", []string{"go", "generics"}).Return(nil)
+
+	assert.NoError(t, svc.SetTags(context.Background(), "article-1", []string{"go", "generics"}))
+	require.Len(t, repo.ops, 1)
+	_, err := svc.Flush(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, repo.ops)
+	repo.AssertExpectations(t)
+	notion.AssertExpectations(t)
+}
+
+func TestAdd(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	article := readings.Article{Title: "Go", URL: "https://go.dev", Tags: []string{"go"}}
+	created := article
+	created.ID = "article-1"
+	repo.On("Find", mock.Anything, "https://go.dev").Return(nil, readings.ErrNotFound).Once()
+	notion.On("CreateArticle", mock.Anything, article).Return(created, nil)
+	repo.On("SaveUpsert", mock.Anything, []readings.Article{created}).Return(nil)
+
+	got, err := svc.Add(ctx, article)
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	// Adding the URL again leaves Notion alone.
+	repo.On("Find", mock.Anything, "https://go.dev").Return(&created, nil)
+	_, err = svc.Add(ctx, article)
+	assert.ErrorIs(t, err, readings.ErrDuplicate)
+	notion.AssertNumberOfCalls(t, "CreateArticle", 1)
+	repo.AssertExpectations(t)
+}
+
+func TestCurrentWeekReadingList_FetchesOnce(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	week := thisWeek("article-1")
+
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(week, nil).Once()
+
+	ids, err := svc.CurrentWeekReadingList(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"article-1"}, ids)
+
+	_, err = svc.ToggleReadingInCurrentWeek(context.Background(), "article-2")
+	assert.NoError(t, err)
+
+	ids, err = svc.CurrentWeekReadingList(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"article-1", "article-2"}, ids)
+	notion.AssertExpectations(t)
+}
+
+func TestMoveInWeekAt(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	week := thisWeek("a", "b", "c")
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(week, nil)
+	// Someone added "d" in Notion meanwhile; the move keeps it.
+	notion.On("FetchWeek", mock.Anything, "week-1").Return(&readings.Week{ID: "week-1", ReadingListIDs: []string{"a", "b", "c", "d"}}, nil)
+	notion.On("UpdateWeekReadingList", mock.Anything, "week-1", []string{"c", "a", "b", "d"}).Return(nil)
+
+	require.NoError(t, svc.MoveInWeekAt(ctx, time.Now(), "c", 0))
+	current, err := svc.CurrentWeek(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a", "b"}, current.ReadingListIDs)
+	_, err = svc.Flush(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, repo.ops)
+	notion.AssertExpectations(t)
+
+	// Positions past the end move to the end; a no-op move queues nothing.
+	require.NoError(t, svc.MoveInWeekAt(ctx, time.Now(), "c", 10))
+	current, _ = svc.CurrentWeek(ctx)
+	assert.Equal(t, []string{"a", "b", "c"}, current.ReadingListIDs)
+	require.Len(t, repo.ops, 1)
+	assert.Equal(t, readings.OpMoveInWeek, repo.ops[0].Kind)
+	assert.Equal(t, 10, repo.ops[0].Position)
+
+	require.NoError(t, svc.MoveInWeekAt(ctx, time.Now(), "c", 2))
+	assert.Len(t, repo.ops, 1)
+}
+
+func TestToggleReadingInWeekAt_Concurrent(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(thisWeek(), nil)
+
+	// The TUI edits the week from several commands at once.
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.ToggleReadingInWeekAt(ctx, time.Now(), fmt.Sprint(i))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	current, err := svc.CurrentWeek(ctx)
+	require.NoError(t, err)
+	assert.Len(t, current.ReadingListIDs, 10)
+	assert.Len(t, repo.ops, 10)
+	notion.AssertExpectations(t)
+}
+
+func TestToggleReadingInWeekAt_DoesNotWaitOnNotion(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	current := thisWeek("a")
+	next := &readings.Week{ID: "week-2", Start: current.End.Add(time.Second), End: current.End.AddDate(0, 0, 7)}
+	nextMonday := next.Start.Add(time.Hour)
+	release := make(chan time.Time)
+	notion.On("FetchWeekAt", mock.Anything, nextMonday).WaitUntil(release).Return(next, nil).Once()
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(current, nil).Once()
+	_, err := svc.CurrentWeek(ctx)
+	require.NoError(t, err)
+
+	loaded := make(chan error)
+	go func() {
+		_, err := svc.WeekAt(ctx, nextMonday)
+		loaded <- err
+	}()
+
+	// The current week can be edited while the next one is being fetched.
+	added, err := svc.ToggleReadingInCurrentWeek(ctx, "b")
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	close(release)
+	require.NoError(t, <-loaded)
+	notion.AssertExpectations(t)
+}
+
+func TestReorderWeekAt(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(thisWeek("a", "b", "c", "d"), nil)
+
+	// "x" is not on the list and "d" is missing from the order.
+	require.NoError(t, svc.ReorderWeekAt(ctx, time.Now(), []string{"c", "x", "a", "b"}))
+	current, err := svc.CurrentWeek(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a", "b", "d"}, current.ReadingListIDs)
+	require.Len(t, repo.ops, 1)
+	assert.Equal(t, "c", repo.ops[0].ArticleID)
+	assert.Equal(t, 0, repo.ops[0].Position)
+
+	// Replaying the queued moves in order gives the same list.
+	require.NoError(t, svc.ReorderWeekAt(ctx, time.Now(), []string{"d", "b", "c", "a"}))
+	current, _ = svc.CurrentWeek(ctx)
+	assert.Equal(t, []string{"d", "b", "c", "a"}, current.ReadingListIDs)
+	replayed := []string{"c", "a", "b", "d"}
+	for _, op := range repo.ops[1:] {
+		require.Equal(t, readings.OpMoveInWeek, op.Kind)
+		replayed = moveTo(replayed, op.ArticleID, op.Position)
+	}
+	assert.Equal(t, current.ReadingListIDs, replayed)
+
+	// The same order queues nothing.
+	queued := len(repo.ops)
+	require.NoError(t, svc.ReorderWeekAt(ctx, time.Now(), []string{"d", "b", "c", "a"}))
+	assert.Len(t, repo.ops, queued)
+}
+
+// moveTo moves id to position, as Notion gets a queued move.
+func moveTo(ids []string, id string, position int) []string {
+	from := slices.Index(ids, id)
+	ids = slices.Delete(slices.Clone(ids), from, from+1)
+	return slices.Insert(ids, position, id)
+}
+
+func TestWeekAt_PlansOtherWeeks(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	current := thisWeek("a")
+	next := &readings.Week{ID: "week-2", Start: current.End.Add(time.Second), End: current.End.AddDate(0, 0, 7), ReadingListIDs: []string{"b"}}
+	nextMonday := next.Start.Add(time.Hour)
+	notion.On("FetchWeekAt", mock.Anything, nextMonday).Return(next, nil).Once()
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(current, nil).Once()
+
+	added, err := svc.ToggleReadingInWeekAt(ctx, nextMonday, "c")
+	require.NoError(t, err)
+	assert.True(t, added)
+	require.Len(t, repo.ops, 1)
+	assert.Equal(t, "week-2", repo.ops[0].WeekID)
+
+	// Later times in the same week reuse it; the current week is separate.
+	week, err := svc.WeekAt(ctx, next.End)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, week.ReadingListIDs)
+	week, err = svc.CurrentWeek(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, week.ReadingListIDs)
+	notion.AssertExpectations(t)
+
+	// Listed weeks include the change still waiting for Notion.
+	notion.On("ListWeeks", mock.Anything, current.Start, next.End).Return([]readings.Week{*current, {ID: "week-2", ReadingListIDs: []string{"b"}}}, nil)
+	weeks, err := svc.ListWeeks(ctx, current.Start, next.End)
+	require.NoError(t, err)
+	require.Len(t, weeks, 2)
+	assert.Equal(t, []string{"a"}, weeks[0].ReadingListIDs)
+	assert.Equal(t, []string{"b", "c"}, weeks[1].ReadingListIDs)
+}
+
+func TestProposeAndApplyPlan(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+	ctx := context.Background()
+
+	article := func(id string, minutes int, tags ...string) readings.Article {
+		return readings.Article{ID: id, Title: id, Tags: tags, ReadingMinutes: minutes}
+	}
+	repo.On("GetAll", mock.Anything).Return([]readings.Article{
+		article("planned", 20, "go"),
+		article("arch-1", 30, "Architecture"),
+		article("arch-2", 30, "architecture"),
+		article("arch-3", 30, "architecture"),
+		article("sec", 15, "security"),
+		article("read-last-week", 5, "security"),
+		article("misc-1", 0),
+		article("misc-2", 0),
+	}, nil)
+
+	week := thisWeek("planned")
+	notion.On("FetchWeekAt", mock.Anything, mock.Anything).Return(week, nil)
+	lastWeek := readings.Week{ID: "week-0", Start: week.Start.AddDate(0, 0, -7), End: week.Start.Add(-time.Second), ReadingListIDs: []string{"read-last-week"}}
+	notion.On("ListWeeks", mock.Anything, week.Start.AddDate(0, 0, -14), week.Start.Add(-time.Second)).Return([]readings.Week{lastWeek}, nil)
+
+	opts := readings.PlanOptions{
+		Count:      5,
+		Quotas:     map[string]int{"architecture": 2, "security": 1},
+		AvoidWeeks: 2,
+	}
+	plan, err := svc.ProposePlan(ctx, time.Now(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"planned"}, titles(plan.Planned))
+	require.Len(t, plan.Proposed, 4)
+	assert.Len(t, tagged(plan.Proposed, "architecture"), 2)
+	assert.Equal(t, []string{"sec"}, titles(tagged(plan.Proposed, "security")))
+
+	// Within a budget, articles without a reading time count as
+	// DefaultReadingMinutes and quotas give way to the budget.
+	opts = readings.PlanOptions{Budget: 60, Quotas: map[string]int{"architecture": 2, "security": 1}, AvoidWeeks: 2}
+	plan, err = svc.ProposePlan(ctx, time.Now(), opts)
+	require.NoError(t, err)
+	require.Len(t, plan.Proposed, 2)
+	assert.Len(t, tagged(plan.Proposed, "architecture"), 1)
+	assert.Empty(t, plan.Proposed[1].Tags)
+	assert.Equal(t, 60, plan.Minutes)
+
+	_, err = svc.ProposePlan(ctx, time.Now(), readings.PlanOptions{})
+	assert.ErrorContains(t, err, "count or a reading time budget")
+
+	// The accepted plan reaches Notion in one update.
+	want := []string{"planned", plan.Proposed[0].ID, plan.Proposed[1].ID}
+	notion.On("FetchWeek", mock.Anything, "week-1").Return(thisWeek("planned"), nil).Once()
+	notion.On("UpdateWeekReadingList", mock.Anything, "week-1", want).Return(nil).Once()
+	require.NoError(t, svc.ApplyPlan(ctx, plan))
+	current, err := svc.CurrentWeek(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, current.ReadingListIDs)
+	_, err = svc.Flush(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, repo.ops)
+	notion.AssertExpectations(t)
+
+	// Every proposal counts as shown; only the accepted one as planned.
+	assert.Len(t, repo.recorded(readings.EventShown), 6)
+	assert.Equal(t, want[1:], repo.recorded(readings.EventPlanned))
+}
+
+func tagged(articles []readings.Article, tag string) []readings.Article {
+	var result []readings.Article
+	for _, a := range articles {
+		if (readings.TagFilter{Include: []string{tag}}).Matches(a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func TestMarkDone(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	notion.On("MarkDone", mock.Anything, "article-1").Return(nil)
+	repo.On("MarkRemoved", mock.Anything, []string{"article-1"}, readings.RemovedDone).Return(nil)
+
+	assert.NoError(t, svc.MarkDone(context.Background(), "article-1"))
+	notion.AssertNotCalled(t, "MarkDone", mock.Anything, mock.Anything)
+	_, err := svc.Flush(context.Background())
+	assert.NoError(t, err)
+	notion.AssertExpectations(t)
+	repo.AssertExpectations(t)
+	assert.Equal(t, []string{"article-1"}, repo.recorded(readings.EventDone))
+}
+
+func TestMarkDone_OfflineQueues(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	repo.On("MarkRemoved", mock.Anything, []string{"article-1"}, readings.RemovedDone).Return(nil)
+	notion.On("MarkDone", mock.Anything, "article-1").Return(assert.AnError)
+
+	assert.NoError(t, svc.MarkDone(context.Background(), "article-1"))
+	repo.AssertExpectations(t)
+	_, err := svc.Flush(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+
+	ops, err := svc.Outbox(context.Background())
+	assert.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Equal(t, readings.OpMarkDone, ops[0].Kind)
+	assert.Equal(t, assert.AnError.Error(), ops[0].LastError)
+}
+
+func TestList(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	cached := []readings.Article{
+		{ID: "1", Title: "zebra", Tags: []string{"Go"}},
+		{ID: "2", Title: "Apple", Tags: []string{"golang"}},
+		{ID: "3", Title: "mango", Tags: []string{"go", "rust"}},
+	}
+	repo.On("GetAll", mock.Anything).Return(cached, nil)
+
+	all, err := svc.List(context.Background(), readings.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apple", "mango", "zebra"}, titles(all))
+
+	tagged, err := svc.List(context.Background(), readings.ListOptions{
+		Tags: readings.TagFilter{Include: []string{"go"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mango", "zebra"}, titles(tagged))
+
+	excluded, err := svc.List(context.Background(), readings.ListOptions{
+		Tags: readings.TagFilter{Include: []string{"go", "golang"}, Exclude: []string{"RUST"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apple", "zebra"}, titles(excluded))
+
+	both, err := svc.List(context.Background(), readings.ListOptions{
+		Tags: readings.TagFilter{Include: []string{"go", "rust"}, MatchAll: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mango"}, titles(both))
+
+	limited, err := svc.List(context.Background(), readings.ListOptions{Limit: 2, Random: true})
+	assert.NoError(t, err)
+	assert.Len(t, limited, 2)
+	assert.Len(t, repo.recorded(readings.EventShown), 2, "A short random list counts as shown")
+	notion.AssertNotCalled(t, "FetchArticles", mock.Anything, mock.Anything)
+}
+
+func titles(articles []readings.Article) []string {
+	result := make([]string, len(articles))
+	for i, a := range articles {
+		result[i] = a.Title
+	}
+	return result
+}
+
+func TestGetReadings_UsesStrategy(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion, readings.WithStrategy(readings.ByPriority{}), readings.WithSeed(1))
+
+	repo.On("GetMatching", mock.Anything, readings.TagFilter{}).Return([]readings.Article{
+		{ID: "low", Priority: 1},
+		{ID: "none"},
+		{ID: "high", Priority: 3},
+	}, nil)
+
+	articles, err := svc.GetReadings(context.Background(), 2, readings.TagFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"high", "low"}, ids(articles))
+	assert.Equal(t, []string{"high", "low"}, repo.recorded(readings.EventShown))
+}
+
+func TestStrategies(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	articles := []readings.Article{
+		{ID: "new", CreatedAt: daysAgo(1), Priority: 1, SurfacedAt: daysAgo(1)},
+		{ID: "unknown", Priority: 3},
+		{ID: "old", CreatedAt: daysAgo(700), SurfacedAt: daysAgo(30)},
+		{ID: "middle", CreatedAt: daysAgo(60), Priority: 1, SurfacedAt: daysAgo(2)},
+	}
+	order := func(s readings.Strategy, seed int64) []string {
+		sorted := append([]readings.Article(nil), articles...)
+		s.Order(sorted, rand.New(rand.NewSource(seed)), now)
+		return ids(sorted)
+	}
+
+	assert.Equal(t, []string{"old", "middle", "new", "unknown"}, order(readings.OldestFirst{}, 1))
+	assert.Equal(t, "unknown", order(readings.ByPriority{}, 1)[0])
+	assert.Equal(t, []string{"unknown", "old", "middle", "new"}, order(readings.LeastSurfaced{}, 1))
+	assert.Equal(t, order(readings.Uniform{}, 7), order(readings.Uniform{}, 7), "The same seed gives the same order")
+	assert.ElementsMatch(t, ids(articles), order(readings.Uniform{}, 7))
+
+	// Age weighting favours old articles without always picking them.
+	oldFirst := 0
+	for seed := int64(0); seed < 200; seed++ {
+		if order(readings.AgeWeighted{}, seed)[0] == "old" {
+			oldFirst++
+		}
+	}
+	assert.Greater(t, oldFirst, 150)
+	assert.Less(t, oldFirst, 200)
+
+	for _, name := range []string{"", "uniform"} {
+		s, err := readings.ParseStrategy(name)
+		require.NoError(t, err)
+		assert.Equal(t, readings.Uniform{}, s)
+	}
+	s, err := readings.ParseStrategy("Least-Surfaced")
+	require.NoError(t, err)
+	assert.Equal(t, readings.LeastSurfaced{}, s)
+	_, err = readings.ParseStrategy("newest")
+	assert.ErrorContains(t, err, "age-weighted")
+}
+
+func ids(articles []readings.Article) []string {
+	result := make([]string, len(articles))
+	for i, a := range articles {
+		result[i] = a.ID
+	}
+	return result
+}
+
+func TestStats(t *testing.T) {
+	repo := new(MockRepository)
+	notion := new(MockNotionClient)
+	svc := readings.NewService(repo, notion)
+
+	day := func(month time.Month, d, hour int) time.Time {
+		return time.Date(2026, month, d, hour, 0, 0, 0, time.Local)
+	}
+	repo.On("GetAll", mock.Anything).Return([]readings.Article{
+		{ID: "waiting", CreatedAt: day(5, 1, 12), Tags: []string{"go"}},
+		{ID: "new", CreatedAt: day(6, 9, 12), Tags: []string{"rust"}},
+	}, nil)
+	repo.On("Removed", mock.Anything).Return([]readings.Removal{
+		{Article: readings.Article{ID: "done-in-notion", CreatedAt: day(4, 1, 12), Tags: []string{"Go"}}, At: day(6, 3, 12), Reason: readings.RemovedDone},
+		{Article: readings.Article{ID: "done-here", CreatedAt: day(5, 20, 12), Tags: []string{"go", "db"}}, At: day(6, 9, 12), Reason: readings.RemovedDone},
+		{Article: readings.Article{ID: "deleted", CreatedAt: day(6, 2, 12)}, At: day(6, 4, 12), Reason: readings.RemovedMissing},
+	}, nil)
+	repo.events = []readings.Event{
+		{Kind: readings.EventOpened, ArticleID: "waiting", At: day(6, 2, 9)},
+		{Kind: readings.EventOpened, ArticleID: "done-here", At: day(6, 8, 9)},
+		{Kind: readings.EventDone, ArticleID: "done-here", At: day(6, 8, 10)},
+	}
+	week := readings.Week{ID: "week-1", Start: day(6, 8, 0), End: day(6, 15, 0).Add(-time.Second), ReadingListIDs: []string{"done-here", "waiting"}}
+	notion.On("ListWeeks", mock.Anything, day(6, 1, 0), week.End).Return([]readings.Week{week}, nil)
+
+	stats, err := svc.Stats(context.Background(), day(6, 10, 12), readings.StatsOptions{Weeks: 2, TopTags: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, stats.Backlog)
+	assert.Equal(t, []readings.WeekStats{
+		{Start: day(6, 1, 0), Added: 1, Completed: 1, Opened: 1, Backlog: 2},
+		{Start: day(6, 8, 0), Added: 1, Completed: 1, Opened: 1, Backlog: 2},
+	}, stats.Weeks)
+	assert.Equal(t, 2, stats.Completed)
+	assert.InDelta(t, (63+18+22.0/24)/2, stats.AverageDays, 0.05, "Done here counts from the history")
+	assert.Equal(t, []readings.PlannedWeek{{Start: week.Start, End: week.End, Planned: 2, Done: 1, Rate: 0.5}}, stats.Planned)
+	assert.Equal(t, []readings.TagStats{{Tag: "go", Read: 2, Saved: 3}, {Tag: "db", Read: 1, Saved: 1}}, stats.Tags)
+
+	_, err = svc.Stats(context.Background(), time.Now(), readings.StatsOptions{})
+	assert.Error(t, err)
+}
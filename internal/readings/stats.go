@@ -0,0 +1,216 @@
+package readings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// StatsOptions sets the period and detail of a report.
+type StatsOptions struct {
+	Weeks   int // Calendar weeks to report, ending with the current one
+	TopTags int // Tags to list; 0 for all
+}
+
+// Stats summarizes reading activity over the last weeks, as far as the local
+// cache and history know it: articles done before the first sync are not
+// counted.
+type Stats struct {
+	From    time.Time `json:"from"` // Monday of the first week reported
+	To      time.Time `json:"to"`   // End of the current week
+	Backlog int       `json:"backlog"`
+
+	Completed   int           `json:"completed"`        // Articles marked done within the period
+	AverageDays float64       `json:"average_age_days"` // Days from saving to done; 0 if unknown
+	Weeks       []WeekStats   `json:"weeks"`            // Oldest first
+	Planned     []PlannedWeek `json:"planned_weeks"`    // Weeks of the weeks database in the period
+	Tags        []TagStats    `json:"tags"`             // Most read first
+}
+
+// WeekStats counts what happened in a calendar week, Monday to Sunday.
+type WeekStats struct {
+	Start     time.Time `json:"start"`
+	Added     int       `json:"added"`     // Articles saved to Notion
+	Completed int       `json:"completed"` // Articles marked done
+	Opened    int       `json:"opened"`    // Articles opened from the TUI
+	Backlog   int       `json:"backlog"`   // Articles not done at the end of the week
+}
+
+// PlannedWeek compares a week's reading list with what has been done since.
+type PlannedWeek struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Planned int       `json:"planned"`
+	Done    int       `json:"done"`
+	Rate    float64   `json:"rate"` // Done over Planned, from 0 to 1
+}
+
+// TagStats compares how many articles with a tag were read and saved, over
+// all time.
+type TagStats struct {
+	Tag   string `json:"tag"`
+	Read  int    `json:"read"`
+	Saved int    `json:"saved"`
+}
+
+// Stats reports on the opts.Weeks calendar weeks up to the one containing
+// now.
+func (s *Service) Stats(ctx context.Context, now time.Time, opts StatsOptions) (Stats, error) {
+	if opts.Weeks <= 0 {
+		return Stats{}, errors.New("stats need at least one week")
+	}
+
+	current := startOfWeek(now)
+	stats := Stats{
+		From: current.AddDate(0, 0, -7*(opts.Weeks-1)),
+		To:   current.AddDate(0, 0, 7).Add(-time.Second),
+	}
+
+	active, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to read cache: %w", err)
+	}
+	removed, err := s.repo.Removed(ctx)
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to read removed articles: %w", err)
+	}
+	events, err := s.History(ctx, HistoryFilter{Kinds: []EventKind{EventOpened, EventDone}})
+	if err != nil {
+		return Stats{}, err
+	}
+	stats.Backlog = len(active)
+
+	// An article marked done here is done when the history says so; one
+	// marked done in Notion when a sync noticed.
+	doneAt := make(map[string]time.Time)
+	for _, r := range removed {
+		if r.Reason == RemovedDone {
+			doneAt[r.ID] = r.At
+		}
+	}
+	for _, e := range events {
+		if _, ok := doneAt[e.ArticleID]; ok && e.Kind == EventDone && e.At.Before(doneAt[e.ArticleID]) {
+			doneAt[e.ArticleID] = e.At
+		}
+	}
+
+	var totalDays float64
+	aged := 0
+	for week := stats.From; !week.After(current); week = week.AddDate(0, 0, 7) {
+		ws := WeekStats{Start: week}
+		end := week.AddDate(0, 0, 7)
+		within := func(t time.Time) bool { return !t.Before(week) && t.Before(end) }
+
+		for _, a := range active {
+			if within(a.CreatedAt) {
+				ws.Added++
+			}
+			if a.CreatedAt.Before(end) {
+				ws.Backlog++
+			}
+		}
+		for _, r := range removed {
+			if within(r.CreatedAt) {
+				ws.Added++
+			}
+			if r.CreatedAt.Before(end) && !r.At.Before(end) {
+				ws.Backlog++
+			}
+			if done, ok := doneAt[r.ID]; ok && within(done) {
+				ws.Completed++
+				if !r.CreatedAt.IsZero() {
+					totalDays += done.Sub(r.CreatedAt).Hours() / 24
+					aged++
+				}
+			}
+		}
+		for _, e := range events {
+			if e.Kind == EventOpened && within(e.At) {
+				ws.Opened++
+			}
+		}
+
+		stats.Completed += ws.Completed
+		stats.Weeks = append(stats.Weeks, ws)
+	}
+	if aged > 0 {
+		stats.AverageDays = totalDays / float64(aged)
+	}
+
+	// The report works offline with the weeks stored so far.
+	weeks, err := s.ListWeeks(ctx, stats.From, stats.To)
+	if err != nil {
+		if weeks, err = s.repo.Weeks(ctx, stats.From, stats.To); err != nil {
+			return Stats{}, fmt.Errorf("failed to read weeks: %w", err)
+		}
+	}
+	for _, w := range weeks {
+		pw := PlannedWeek{Start: w.Start, End: w.End, Planned: len(w.ReadingListIDs)}
+		for _, id := range w.ReadingListIDs {
+			if _, ok := doneAt[id]; ok {
+				pw.Done++
+			}
+		}
+		if pw.Planned > 0 {
+			pw.Rate = float64(pw.Done) / float64(pw.Planned)
+		}
+		stats.Planned = append(stats.Planned, pw)
+	}
+
+	stats.Tags = tagStats(active, removed, doneAt, opts.TopTags)
+	return stats, nil
+}
+
+// tagStats counts read and saved articles per tag, ignoring case, most read
+// first.
+func tagStats(active []Article, removed []Removal, doneAt map[string]time.Time, top int) []TagStats {
+	byKey := make(map[string]*TagStats)
+	count := func(a Article, read bool) {
+		for _, tag := range a.Tags {
+			key := TagKey(tag)
+			ts, ok := byKey[key]
+			if !ok {
+				ts = &TagStats{Tag: tag}
+				byKey[key] = ts
+			}
+			ts.Saved++
+			if read {
+				ts.Read++
+			}
+		}
+	}
+	for _, a := range active {
+		count(a, false)
+	}
+	for _, r := range removed {
+		_, read := doneAt[r.ID]
+		count(r.Article, read)
+	}
+
+	tags := make([]TagStats, 0, len(byKey))
+	for _, ts := range byKey {
+		tags = append(tags, *ts)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Read != tags[j].Read {
+			return tags[i].Read > tags[j].Read
+		}
+		if tags[i].Saved != tags[j].Saved {
+			return tags[i].Saved > tags[j].Saved
+		}
+		return TagKey(tags[i].Tag) < TagKey(tags[j].Tag)
+	})
+	if top > 0 && len(tags) > top {
+		tags = tags[:top]
+	}
+	return tags
+}
+
+// startOfWeek returns local midnight on the Monday of t's week.
+func startOfWeek(t time.Time) time.Time {
+	t = t.Local()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,126 @@
+package readings
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Strategy decides which cached articles are picked first when the app
+// chooses for the user: the readings shown on launch, random lists and plans.
+type Strategy interface {
+	// Name is how the strategy is chosen in the config file and on the
+	// command line.
+	Name() string
+	// Order sorts articles in place, most wanted first. Ties are broken
+	// with rng so that the same seed gives the same order.
+	Order(articles []Article, rng *rand.Rand, now time.Time)
+}
+
+// DefaultStrategy is used when no strategy is configured.
+const DefaultStrategy = "uniform"
+
+// Strategies returns the built-in strategies.
+func Strategies() []Strategy {
+	return []Strategy{Uniform{}, OldestFirst{}, AgeWeighted{}, ByPriority{}, LeastSurfaced{}}
+}
+
+// ParseStrategy returns the built-in strategy with the given name. An empty
+// name selects DefaultStrategy.
+func ParseStrategy(name string) (Strategy, error) {
+	if name == "" {
+		name = DefaultStrategy
+	}
+	var names []string
+	for _, s := range Strategies() {
+		if strings.EqualFold(s.Name(), name) {
+			return s, nil
+		}
+		names = append(names, s.Name())
+	}
+	return nil, fmt.Errorf("unknown strategy %q (want one of %s)", name, strings.Join(names, ", "))
+}
+
+// Uniform gives every article the same chance.
+type Uniform struct{}
+
+func (Uniform) Name() string { return "uniform" }
+
+func (Uniform) Order(articles []Article, rng *rand.Rand, now time.Time) {
+	shuffle(articles, rng)
+}
+
+// OldestFirst picks the articles added to Notion longest ago first. Articles
+// of unknown age come last.
+type OldestFirst struct{}
+
+func (OldestFirst) Name() string { return "oldest" }
+
+func (OldestFirst) Order(articles []Article, rng *rand.Rand, now time.Time) {
+	shuffle(articles, rng)
+	sort.SliceStable(articles, func(i, j int) bool {
+		a, b := articles[i].CreatedAt, articles[j].CreatedAt
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+}
+
+// AgeWeighted picks at random, with an article's chance growing with the
+// days since it was added: a year-old article is about 25 times as likely
+// as a two-week-old one. Articles of unknown age count as new.
+type AgeWeighted struct{}
+
+func (AgeWeighted) Name() string { return "age-weighted" }
+
+func (AgeWeighted) Order(articles []Article, rng *rand.Rand, now time.Time) {
+	// Weighted sampling without replacement (Efraimidis–Spirakis): each
+	// article draws u^(1/weight), compared in log space, highest first.
+	keys := make(map[string]float64, len(articles))
+	for _, a := range articles {
+		weight := 1.0
+		if !a.CreatedAt.IsZero() && now.After(a.CreatedAt) {
+			weight += now.Sub(a.CreatedAt).Hours() / 24
+		}
+		keys[a.ID] = math.Log(1-rng.Float64()) / weight
+	}
+	sort.SliceStable(articles, func(i, j int) bool {
+		return keys[articles[i].ID] > keys[articles[j].ID]
+	})
+}
+
+// ByPriority picks the articles with the highest Notion priority first,
+// in random order within a priority.
+type ByPriority struct{}
+
+func (ByPriority) Name() string { return "priority" }
+
+func (ByPriority) Order(articles []Article, rng *rand.Rand, now time.Time) {
+	shuffle(articles, rng)
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].Priority > articles[j].Priority
+	})
+}
+
+// LeastSurfaced picks the articles the app suggested longest ago first,
+// starting with those it never suggested.
+type LeastSurfaced struct{}
+
+func (LeastSurfaced) Name() string { return "least-surfaced" }
+
+func (LeastSurfaced) Order(articles []Article, rng *rand.Rand, now time.Time) {
+	shuffle(articles, rng)
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].SurfacedAt.Before(articles[j].SurfacedAt)
+	})
+}
+
+func shuffle(articles []Article, rng *rand.Rand) {
+	rng.Shuffle(len(articles), func(i, j int) {
+		articles[i], articles[j] = articles[j], articles[i]
+	})
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"productivity.go/internal/readings"
+)
+
+func (s *SQLite) RecordEvents(ctx context.Context, events []readings.Event) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.PrepareContext(ctx, `
+		INSERT INTO history (kind, article_id, week_id, at) VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	surfaced, err := tx.PrepareContext(ctx, `UPDATE articles SET surfaced_at = ? WHERE id = ?`)
+	if err != nil {
+		return err
+	}
+	defer surfaced.Close()
+
+	for _, e := range events {
+		if _, err := insert.ExecContext(ctx, string(e.Kind), e.ArticleID, e.WeekID, e.At.UTC()); err != nil {
+			return err
+		}
+		if e.Kind == readings.EventShown {
+			if _, err := surfaced.ExecContext(ctx, e.At.UTC(), e.ArticleID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLite) History(ctx context.Context, filter readings.HistoryFilter) ([]readings.Event, error) {
+	var where []string
+	var args []interface{}
+	if len(filter.Kinds) > 0 {
+		where = append(where, `history.kind IN (`+strings.TrimSuffix(strings.Repeat("?, ", len(filter.Kinds)), ", ")+`)`)
+		for _, kind := range filter.Kinds {
+			args = append(args, string(kind))
+		}
+	}
+	if filter.Article != "" {
+		where = append(where, `(replace(history.article_id, '-', '') = replace(?, '-', '') OR articles.url = ?)`)
+		args = append(args, filter.Article, filter.Article)
+	}
+	query := `
+		SELECT history.id, history.kind, history.article_id, articles.title, history.week_id, history.at
+		FROM history LEFT JOIN articles ON articles.id = history.article_id`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ORDER BY history.id DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Stored times do not compare reliably as text, so the time range is
+	// checked here rather than in SQL.
+	var events []readings.Event
+	for rows.Next() {
+		var e readings.Event
+		var kind string
+		var title sql.NullString
+		if err := rows.Scan(&e.ID, &kind, &e.ArticleID, &title, &e.WeekID, &e.At); err != nil {
+			return nil, err
+		}
+		if !inRange(e.At, filter.Since, filter.Until) {
+			continue
+		}
+		e.Kind = readings.EventKind(kind)
+		e.Title = title.String
+		events = append(events, e)
+		if filter.Limit > 0 && len(events) == filter.Limit {
+			break
+		}
+	}
+	return events, rows.Err()
+}
+
+// inRange reports whether since <= t < until, treating zero bounds as open.
+func inRange(t, since, until time.Time) bool {
+	return (since.IsZero() || !t.Before(since)) && (until.IsZero() || t.Before(until))
+}
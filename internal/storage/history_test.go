@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/readings"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	articles := []readings.Article{
+		{ID: "1", Title: "Shown", URL: "https://example.com/1"},
+		{ID: "2", Title: "Finished", URL: "https://example.com/2"},
+	}
+	require.NoError(t, store.SaveUpsert(ctx, articles))
+
+	monday := time.Date(2026, 5, 4, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, store.RecordEvents(ctx, []readings.Event{
+		{Kind: readings.EventShown, ArticleID: "1", At: monday},
+		{Kind: readings.EventShown, ArticleID: "2", At: monday},
+		{Kind: readings.EventPlanned, ArticleID: "2", WeekID: "week-1", At: monday.Add(time.Hour)},
+	}))
+	require.NoError(t, store.RecordEvents(ctx, []readings.Event{
+		{Kind: readings.EventOpened, ArticleID: "2", At: monday.AddDate(0, 0, 1)},
+		{Kind: readings.EventDone, ArticleID: "2", At: monday.AddDate(0, 0, 2)},
+	}))
+	require.NoError(t, store.MarkRemoved(ctx, []string{"2"}, readings.RemovedDone))
+
+	// Shown events keep SurfacedAt up to date, and syncs do not reset it.
+	require.NoError(t, store.SaveUpsert(ctx, articles[:1]))
+	all, err := store.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.True(t, monday.Equal(all[0].SurfacedAt))
+
+	events, err := store.History(ctx, readings.HistoryFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 5)
+	assert.Equal(t, readings.EventDone, events[0].Kind, "Newest first")
+	assert.Equal(t, "Finished", events[0].Title, "Titles of removed articles are kept")
+	assert.Equal(t, "week-1", events[2].WeekID)
+
+	tests := []struct {
+		name   string
+		filter readings.HistoryFilter
+		want   []readings.EventKind
+	}{
+		{"kinds", readings.HistoryFilter{Kinds: []readings.EventKind{readings.EventOpened, readings.EventPlanned}},
+			[]readings.EventKind{readings.EventOpened, readings.EventPlanned}},
+		{"article", readings.HistoryFilter{Article: "1"}, []readings.EventKind{readings.EventShown}},
+		{"url", readings.HistoryFilter{Article: "https://example.com/2", Kinds: []readings.EventKind{readings.EventDone}},
+			[]readings.EventKind{readings.EventDone}},
+		{"range", readings.HistoryFilter{Since: monday.Add(time.Hour), Until: monday.AddDate(0, 0, 2)},
+			[]readings.EventKind{readings.EventOpened, readings.EventPlanned}},
+		{"limit", readings.HistoryFilter{Article: "2", Limit: 2}, []readings.EventKind{readings.EventDone, readings.EventOpened}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := store.History(ctx, tt.filter)
+			require.NoError(t, err)
+			kinds := make([]readings.EventKind, len(events))
+			for i, e := range events {
+				kinds[i] = e.Kind
+			}
+			assert.Equal(t, tt.want, kinds)
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"productivity.go/internal/readings"
+)
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer
+// version of the binary than the one opening it.
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of readings")
+
+// migrations lists the schema changes in order. The schema version stored in
+// PRAGMA user_version is the number of migrations applied, so entries must
+// only ever be appended, never edited or reordered.
+var migrations = []string{
+	// 1: articles cache. IF NOT EXISTS adopts databases created before the
+	// schema was versioned, which have this table but user_version 0.
+	`CREATE TABLE IF NOT EXISTS articles (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		url TEXT NOT NULL,
+		tags TEXT, -- Stored as JSON string
+		fetched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`,
+
+	// 2: per-database cursor for incremental syncs.
+	`CREATE TABLE sync_state (
+		database_id TEXT PRIMARY KEY,
+		last_synced_at TIMESTAMP NOT NULL
+	);`,
+
+	// 3: tombstones for articles that are done or gone from Notion.
+	`ALTER TABLE articles ADD COLUMN removed_at TIMESTAMP;
+	ALTER TABLE articles ADD COLUMN removed_reason TEXT;`,
+
+	// 4: normalized tags for exact matching. articles.tags stays as the
+	// ordered copy returned to callers.
+	`CREATE TABLE article_tags (
+		article_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		tag_key TEXT NOT NULL, -- readings.TagKey of tag
+		PRIMARY KEY (article_id, tag_key)
+	);
+	CREATE INDEX article_tags_tag_key ON article_tags (tag_key, article_id);`,
+
+	// 5: full-text index over articles, kept in sync by triggers. It stores
+	// its own copy of the text so it does not depend on articles' rowids.
+	`CREATE VIRTUAL TABLE articles_fts USING fts5(
+		article_id UNINDEXED, title, url, tags,
+		tokenize = 'unicode61 remove_diacritics 2'
+	);
+	CREATE TRIGGER articles_fts_insert AFTER INSERT ON articles BEGIN
+		INSERT INTO articles_fts (article_id, title, url, tags) VALUES (new.id, new.title, new.url, new.tags);
+	END;
+	CREATE TRIGGER articles_fts_update AFTER UPDATE OF title, url, tags ON articles BEGIN
+		DELETE FROM articles_fts WHERE article_id = old.id;
+		INSERT INTO articles_fts (article_id, title, url, tags) VALUES (new.id, new.title, new.url, new.tags);
+	END;
+	CREATE TRIGGER articles_fts_delete AFTER DELETE ON articles BEGIN
+		DELETE FROM articles_fts WHERE article_id = old.id;
+	END;
+	INSERT INTO articles_fts (article_id, title, url, tags)
+		SELECT id, title, url, tags FROM articles;`,
+
+	// 6: article metadata for the detail view, with notes added to the
+	// full-text index. Clearing the sync cursors makes the next sync refetch
+	// every article to fill it in.
+	`ALTER TABLE articles ADD COLUMN created_at TIMESTAMP;
+	ALTER TABLE articles ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+	ALTER TABLE articles ADD COLUMN reading_minutes INTEGER NOT NULL DEFAULT 0;
+	DROP TRIGGER articles_fts_insert;
+	DROP TRIGGER articles_fts_update;
+	DROP TRIGGER articles_fts_delete;
+	DROP TABLE articles_fts;
+	CREATE VIRTUAL TABLE articles_fts USING fts5(
+		article_id UNINDEXED, title, url, tags, notes,
+		tokenize = 'unicode61 remove_diacritics 2'
+	);
+	CREATE TRIGGER articles_fts_insert AFTER INSERT ON articles BEGIN
+		INSERT INTO articles_fts (article_id, title, url, tags, notes) VALUES (new.id, new.title, new.url, new.tags, new.notes);
+	END;
+	CREATE TRIGGER articles_fts_update AFTER UPDATE OF title, url, tags, notes ON articles BEGIN
+		DELETE FROM articles_fts WHERE article_id = old.id;
+		INSERT INTO articles_fts (article_id, title, url, tags, notes) VALUES (new.id, new.title, new.url, new.tags, new.notes);
+	END;
+	CREATE TRIGGER articles_fts_delete AFTER DELETE ON articles BEGIN
+		DELETE FROM articles_fts WHERE article_id = old.id;
+	END;
+	INSERT INTO articles_fts (article_id, title, url, tags, notes)
+		SELECT id, title, url, tags, notes FROM articles;
+	DELETE FROM sync_state;`,
+
+	// 7: changes made offline, waiting to be pushed to Notion.
+	`CREATE TABLE outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		article_id TEXT NOT NULL,
+		week_id TEXT NOT NULL DEFAULT '',
+		tags TEXT, -- JSON array, for set_tags
+		created_at TIMESTAMP NOT NULL,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		failed INTEGER NOT NULL DEFAULT 0 -- Rejected by Notion, not retried
+	);`,
+
+	// 8: local copy of the weeks seen, so the reading list can be edited
+	// offline.
+	`CREATE TABLE weeks (
+		id TEXT PRIMARY KEY,
+		starts_at TIMESTAMP NOT NULL,
+		ends_at TIMESTAMP NOT NULL,
+		reading_list TEXT NOT NULL -- JSON array of article IDs
+	);`,
+
+	// 9: target index of queued reading list moves.
+	`ALTER TABLE outbox ADD COLUMN position INTEGER NOT NULL DEFAULT 0;`,
+
+	// 10: Notion priority and when the app last suggested an article, for
+	// the selection strategies. Clearing the sync cursors refetches every
+	// article to fill in the priority.
+	`ALTER TABLE articles ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE articles ADD COLUMN surfaced_at TIMESTAMP;
+	DELETE FROM sync_state;`,
+
+	// 11: what the app showed and what was done with articles. surfaced_at
+	// stays as the time of each article's latest shown event.
+	`CREATE TABLE history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		article_id TEXT NOT NULL,
+		week_id TEXT NOT NULL DEFAULT '',
+		at TIMESTAMP NOT NULL
+	);
+	CREATE INDEX history_article ON history (article_id);
+	INSERT INTO history (kind, article_id, at)
+		SELECT 'shown', id, surfaced_at FROM articles WHERE surfaced_at IS NOT NULL;`,
+}
+
+// backfills fill in data a migration's SQL cannot compute, keyed by schema
+// version. Each runs in the same transaction, after the migration's SQL.
+var backfills = map[int]func(ctx context.Context, tx *sql.Tx) error{
+	4: backfillArticleTags,
+}
+
+// backfillArticleTags fills article_tags from the cached articles, folding
+// tags in Go rather than with SQLite's lower(), which only folds ASCII.
+func backfillArticleTags(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, tags FROM articles")
+	if err != nil {
+		return err
+	}
+	tagsByID := make(map[string][]string)
+	for rows.Next() {
+		var id string
+		var tagsJSON sql.NullString
+		if err := rows.Scan(&id, &tagsJSON); err != nil {
+			rows.Close()
+			return err
+		}
+		var tags []string
+		if json.Unmarshal([]byte(tagsJSON.String), &tags) == nil {
+			tagsByID[id] = tags
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, tags := range tagsByID {
+		for _, tag := range tags {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT OR IGNORE INTO article_tags (article_id, tag, tag_key) VALUES (?, ?, ?)",
+				id, tag, readings.TagKey(tag)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LatestSchemaVersion is the schema version this binary migrates to.
+func LatestSchemaVersion() int {
+	return len(migrations)
+}
+
+// SchemaVersion returns the schema version of the open database.
+func (s *SQLite) SchemaVersion(ctx context.Context) (int, error) {
+	return schemaVersion(ctx, s.db)
+}
+
+func schemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// FileSchemaVersion reads the schema version of the database at path
+// without migrating or otherwise writing to it.
+func FileSchemaVersion(ctx context.Context, path string) (int, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+	defer db.Close()
+	return schemaVersion(ctx, db)
+}
+
+// migrate brings the database up to the latest schema version.
+func migrate(ctx context.Context, db *sql.DB) error {
+	return migrateTo(ctx, db, len(migrations))
+}
+
+// migrateTo applies the pending migrations up to and including target, each
+// in its own transaction together with the version bump.
+func migrateTo(ctx context.Context, db *sql.DB, target int) error {
+	current, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("%w (database version %d, supported %d)", ErrSchemaTooNew, current, len(migrations))
+	}
+
+	for version := current + 1; version <= target; version++ {
+		if err := applyMigration(ctx, db, version); err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, version int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migrations[version-1]); err != nil {
+		return err
+	}
+	if backfill, ok := backfills[version]; ok {
+		if err := backfill(ctx, tx); err != nil {
+			return err
+		}
+	}
+	// PRAGMA does not accept bound parameters.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
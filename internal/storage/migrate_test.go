@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/readings"
+)
+
+// legacySchema is the articles table as created before schema versioning.
+const legacySchema = `
+	CREATE TABLE IF NOT EXISTS articles (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		url TEXT NOT NULL,
+		tags TEXT, -- Stored as JSON string
+		fetched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	`
+
+// newFixture creates a database at the given schema version holding one article.
+func newFixture(t *testing.T, version int) string {
+	t.Helper()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), DBFileName)
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	if version == 0 {
+		_, err = db.ExecContext(ctx, legacySchema)
+	} else {
+		err = migrateTo(ctx, db, version)
+	}
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO articles (id, title, url, tags, fetched_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		"page-1", "Fixture Article", "https://example.com", `["Go"]`)
+	require.NoError(t, err)
+
+	if version >= 2 {
+		_, err = db.ExecContext(ctx, `INSERT INTO sync_state (database_id, last_synced_at) VALUES ('db-1', CURRENT_TIMESTAMP)`)
+		require.NoError(t, err)
+	}
+
+	if version >= 4 {
+		_, err = db.ExecContext(ctx, `INSERT INTO article_tags (article_id, tag, tag_key) VALUES ('page-1', 'Go', 'go')`)
+		require.NoError(t, err)
+	}
+
+	return path
+}
+
+func TestOpen_UpgradesEveryPastVersion(t *testing.T) {
+	for version := 0; version <= LatestSchemaVersion(); version++ {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			ctx := context.Background()
+			store, err := Open(newFixture(t, version))
+			require.NoError(t, err)
+			defer store.Close()
+
+			got, err := store.SchemaVersion(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, LatestSchemaVersion(), got)
+
+			articles, err := store.GetAll(ctx)
+			require.NoError(t, err)
+			require.Len(t, articles, 1)
+			assert.Equal(t, "Fixture Article", articles[0].Title)
+			assert.Equal(t, []string{"Go"}, articles[0].Tags)
+
+			tagged, err := store.GetMatching(ctx, readings.TagFilter{Include: []string{"go"}})
+			require.NoError(t, err)
+			assert.Len(t, tagged, 1)
+
+			found, err := store.Search(ctx, "fixture", 10)
+			require.NoError(t, err)
+			assert.Len(t, found, 1)
+
+			// Metadata added later is refetched by clearing the sync cursor.
+			if version < 10 {
+				cursor, err := store.GetSyncCursor(ctx, "db-1")
+				require.NoError(t, err)
+				assert.True(t, cursor.IsZero())
+			}
+		})
+	}
+}
+
+func TestOpen_FoldsTagsBeyondASCII(t *testing.T) {
+	ctx := context.Background()
+	path := newFixture(t, 3)
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE articles SET tags = '["ÉCLAIR"]'`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store, err := Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	tagged, err := store.GetMatching(ctx, readings.TagFilter{Include: []string{"éclair"}})
+	require.NoError(t, err)
+	assert.Len(t, tagged, 1)
+}
+
+func TestOpen_FreshDatabase(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(filepath.Join(t.TempDir(), DBFileName))
+	require.NoError(t, err)
+	defer store.Close()
+
+	got, err := store.SchemaVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, LatestSchemaVersion(), got)
+}
+
+func TestOpen_RefusesNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DBFileName)
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(fmt.Sprintf("PRAGMA user_version = %d", LatestSchemaVersion()+1))
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = Open(path)
+	assert.ErrorIs(t, err, ErrSchemaTooNew)
+}
+
+func TestMigrate_FailedMigrationRollsBack(t *testing.T) {
+	ctx := context.Background()
+	path := newFixture(t, LatestSchemaVersion())
+
+	original := migrations
+	migrations = append(append([]string{}, original...),
+		`CREATE TABLE half_done (id TEXT); INSERT INTO missing_table VALUES (1);`)
+	defer func() { migrations = original }()
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	assert.Error(t, migrate(ctx, db))
+
+	version, err := schemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, len(original), version)
+
+	var name string
+	err = db.QueryRowContext(ctx, `SELECT name FROM sqlite_master WHERE name = 'half_done'`).Scan(&name)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
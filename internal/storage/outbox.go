@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"productivity.go/internal/readings"
+)
+
+func (s *SQLite) Enqueue(ctx context.Context, op readings.PendingOp) error {
+	var tagsJSON []byte
+	if op.Kind == readings.OpSetTags {
+		var err error
+		if tagsJSON, err = json.Marshal(op.Tags); err != nil {
+			return fmt.Errorf("failed to marshal tags for article %s: %w", op.ArticleID, err)
+		}
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO outbox (kind, article_id, week_id, tags, position, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, string(op.Kind), op.ArticleID, op.WeekID, string(tagsJSON), op.Position, op.CreatedAt.UTC())
+	return err
+}
+
+func (s *SQLite) PendingOps(ctx context.Context) ([]readings.PendingOp, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, kind, article_id, week_id, tags, position, created_at, attempts, last_error, failed
+		FROM outbox ORDER BY id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ops []readings.PendingOp
+	for rows.Next() {
+		var op readings.PendingOp
+		var kind, tagsJSON string
+		if err := rows.Scan(&op.ID, &kind, &op.ArticleID, &op.WeekID, &tagsJSON, &op.Position, &op.CreatedAt, &op.Attempts, &op.LastError, &op.Failed); err != nil {
+			return nil, err
+		}
+		op.Kind = readings.OpKind(kind)
+		if tagsJSON != "" {
+			if err := json.Unmarshal([]byte(tagsJSON), &op.Tags); err != nil {
+				return nil, fmt.Errorf("failed to parse tags of change %d: %w", op.ID, err)
+			}
+		}
+		ops = append(ops, op)
+	}
+	return ops, rows.Err()
+}
+
+func (s *SQLite) RecordOpFailure(ctx context.Context, id int64, message string, failed bool) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox SET attempts = attempts + 1, last_error = ?, failed = ?
+		WHERE id = ?
+	`, message, failed, id)
+	return err
+}
+
+func (s *SQLite) DeleteOps(ctx context.Context, ids []int64) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM outbox WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
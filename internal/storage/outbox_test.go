@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/readings"
+)
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	queuedAt := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Enqueue(ctx, readings.PendingOp{Kind: readings.OpAddToWeek, ArticleID: "1", WeekID: "week-1", CreatedAt: queuedAt}))
+	require.NoError(t, store.Enqueue(ctx, readings.PendingOp{Kind: readings.OpSetTags, ArticleID: "2", Tags: []string{"go", "db"}, CreatedAt: queuedAt}))
+	require.NoError(t, store.Enqueue(ctx, readings.PendingOp{Kind: readings.OpMarkDone, ArticleID: "3", CreatedAt: queuedAt}))
+	require.NoError(t, store.Enqueue(ctx, readings.PendingOp{Kind: readings.OpMoveInWeek, ArticleID: "1", WeekID: "week-1", Position: 2, CreatedAt: queuedAt}))
+
+	ops, err := store.PendingOps(ctx)
+	require.NoError(t, err)
+	require.Len(t, ops, 4)
+	assert.Equal(t, 2, ops[3].Position)
+	_, err = store.DeleteOps(ctx, []int64{ops[3].ID})
+	require.NoError(t, err)
+	assert.Equal(t, readings.OpAddToWeek, ops[0].Kind)
+	assert.Equal(t, "week-1", ops[0].WeekID)
+	assert.Nil(t, ops[0].Tags)
+	assert.Equal(t, []string{"go", "db"}, ops[1].Tags)
+	assert.True(t, queuedAt.Equal(ops[2].CreatedAt))
+
+	require.NoError(t, store.RecordOpFailure(ctx, ops[0].ID, "offline", false))
+	require.NoError(t, store.RecordOpFailure(ctx, ops[0].ID, "page deleted", true))
+
+	n, err := store.DeleteOps(ctx, []int64{ops[1].ID, ops[2].ID, 999})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	ops, err = store.PendingOps(ctx)
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Equal(t, 2, ops[0].Attempts)
+	assert.Equal(t, "page deleted", ops[0].LastError)
+	assert.True(t, ops[0].Failed)
+}
+
+func TestWeekAt(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	_, err := store.WeekAt(ctx, time.Now())
+	assert.ErrorIs(t, err, readings.ErrNotFound)
+
+	monday := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	week := readings.Week{ID: "week-1", Start: monday, End: monday.AddDate(0, 0, 7).Add(-time.Second)}
+	require.NoError(t, store.SaveWeek(ctx, week))
+	next := readings.Week{ID: "week-2", Start: week.Start.AddDate(0, 0, 7), End: week.End.AddDate(0, 0, 7)}
+	require.NoError(t, store.SaveWeek(ctx, next))
+
+	week.ReadingListIDs = []string{"a", "b"}
+	require.NoError(t, store.SaveWeek(ctx, week))
+
+	got, err := store.WeekAt(ctx, monday.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.Equal(t, "week-1", got.ID)
+	assert.Equal(t, []string{"a", "b"}, got.ReadingListIDs)
+
+	got, err = store.WeekAt(ctx, monday.AddDate(0, 0, 8))
+	require.NoError(t, err)
+	assert.Equal(t, "week-2", got.ID)
+	assert.Empty(t, got.ReadingListIDs)
+
+	_, err = store.WeekAt(ctx, monday.AddDate(0, 0, 20))
+	assert.ErrorIs(t, err, readings.ErrNotFound)
+
+	weeks, err := store.Weeks(ctx, monday.AddDate(0, 0, 6), monday.AddDate(0, 0, 30))
+	require.NoError(t, err)
+	require.Len(t, weeks, 2)
+	assert.Equal(t, "week-1", weeks[0].ID)
+	assert.Equal(t, "week-2", weeks[1].ID)
+	weeks, err = store.Weeks(ctx, monday.AddDate(0, 0, 8), monday.AddDate(0, 0, 9))
+	require.NoError(t, err)
+	require.Len(t, weeks, 1)
+	assert.Equal(t, "week-2", weeks[0].ID)
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	_ "modernc.org/sqlite"
 	"productivity.go/internal/readings"
@@ -17,49 +20,50 @@ const (
 	DBDirName  = "productivity.go"
 )
 
+// articleColumns are the columns read by scanArticles.
+const articleColumns = `articles.id, articles.title, articles.url, articles.tags, articles.fetched_at,
+	articles.created_at, articles.notes, articles.reading_minutes, articles.priority, articles.surfaced_at`
+
 type SQLite struct {
 	db *sql.DB
 }
 
-// NewSQLite creates a new SQLite storage instance.
+// NewSQLite creates a new SQLite storage instance in the user's config directory.
 func NewSQLite() (*SQLite, error) {
-	home, err := os.UserHomeDir()
+	dbPath, err := DefaultPath()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home dir: %w", err)
+		return nil, err
 	}
 
-	dbDir := filepath.Join(home, ".config", DBDirName)
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create db directory: %w", err)
 	}
 
-	dbPath := filepath.Join(dbDir, DBFileName)
-	db, err := sql.Open("sqlite", dbPath)
+	return Open(dbPath)
+}
+
+// DefaultPath returns the location of the article cache.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home dir: %w", err)
+	}
+	return filepath.Join(home, ".config", DBDirName, DBFileName), nil
+}
+
+// Open opens the SQLite database at path and migrates it to the latest schema.
+func Open(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite: %w", err)
 	}
 
-	store := &SQLite{db: db}
-	if err := store.migrate(); err != nil {
+	if err := migrate(context.Background(), db); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to migrate db: %w", err)
 	}
 
-	return store, nil
-}
-
-func (s *SQLite) migrate() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS articles (
-		id TEXT PRIMARY KEY,
-		title TEXT NOT NULL,
-		url TEXT NOT NULL,
-		tags TEXT, -- Stored as JSON string
-		fetched_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-	`
-	_, err := s.db.Exec(query)
-	return err
+	return &SQLite{db: db}, nil
 }
 
 func (s *SQLite) Close() error {
@@ -74,47 +78,71 @@ func (s *SQLite) SaveUpsert(ctx context.Context, articles []readings.Article) er
 	defer tx.Rollback()
 
 	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO articles (id, title, url, tags, fetched_at)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO articles (id, title, url, tags, fetched_at, created_at, notes, reading_minutes, priority)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT (id) DO UPDATE SET
 			title = excluded.title,
 			url = excluded.url,
 			tags = excluded.tags,
-			fetched_at = excluded.fetched_at
+			fetched_at = excluded.fetched_at,
+			created_at = excluded.created_at,
+			notes = excluded.notes,
+			reading_minutes = excluded.reading_minutes,
+			priority = excluded.priority,
+			removed_at = NULL,
+			removed_reason = NULL
 	`)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
+	deleteTags, err := tx.PrepareContext(ctx, `DELETE FROM article_tags WHERE article_id = ?`)
+	if err != nil {
+		return err
+	}
+	defer deleteTags.Close()
+
+	insertTag, err := tx.PrepareContext(ctx, `
+		INSERT OR IGNORE INTO article_tags (article_id, tag, tag_key) VALUES (?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer insertTag.Close()
+
 	for _, a := range articles {
 		tagsJSON, err := json.Marshal(a.Tags)
 		if err != nil {
 			return fmt.Errorf("failed to marshal tags for article %s: %w", a.ID, err)
 		}
 
-		_, err = stmt.ExecContext(ctx, a.ID, a.Title, a.URL, string(tagsJSON), a.FetchedAt)
+		var createdAt sql.NullTime
+		if !a.CreatedAt.IsZero() {
+			createdAt = sql.NullTime{Time: a.CreatedAt.UTC(), Valid: true}
+		}
+
+		_, err = stmt.ExecContext(ctx, a.ID, a.Title, a.URL, string(tagsJSON), a.FetchedAt, createdAt, a.Notes, a.ReadingMinutes, a.Priority)
 		if err != nil {
 			return err
 		}
+
+		if _, err := deleteTags.ExecContext(ctx, a.ID); err != nil {
+			return err
+		}
+		for _, tag := range a.Tags {
+			if _, err := insertTag.ExecContext(ctx, a.ID, tag, readings.TagKey(tag)); err != nil {
+				return err
+			}
+		}
 	}
 
 	return tx.Commit()
 }
 
-func (s *SQLite) GetRandom(ctx context.Context, count int, tag string) ([]readings.Article, error) {
-	var query string
-	var args []interface{}
-
-	if tag != "" {
-		// SQLite doesn't have ILIKE by default, but modernc might support it or we use LIKE with upper/lower.
-		// modernc/sqlite supports LIKE which is case-insensitive for ASCII by default.
-		query = `SELECT id, title, url, tags, fetched_at FROM articles WHERE tags LIKE ? ORDER BY random() LIMIT ?`
-		args = []interface{}{"%" + tag + "%", count}
-	} else {
-		query = `SELECT id, title, url, tags, fetched_at FROM articles ORDER BY random() LIMIT ?`
-		args = []interface{}{count}
-	}
+func (s *SQLite) GetMatching(ctx context.Context, filter readings.TagFilter) ([]readings.Article, error) {
+	where, args := tagFilterClause(filter)
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE removed_at IS NULL` + where
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -126,7 +154,7 @@ func (s *SQLite) GetRandom(ctx context.Context, count int, tag string) ([]readin
 }
 
 func (s *SQLite) GetAll(ctx context.Context) ([]readings.Article, error) {
-	query := `SELECT id, title, url, tags, fetched_at FROM articles`
+	query := `SELECT ` + articleColumns + ` FROM articles WHERE removed_at IS NULL`
 	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -136,23 +164,215 @@ func (s *SQLite) GetAll(ctx context.Context) ([]readings.Article, error) {
 	return scanArticles(rows)
 }
 
-func scanArticles(rows *sql.Rows) ([]readings.Article, error) {
-	var articles []readings.Article
+func (s *SQLite) Removed(ctx context.Context) ([]readings.Removal, error) {
+	query := `SELECT ` + articleColumns + `, removed_at, removed_reason FROM articles WHERE removed_at IS NOT NULL`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var removals []readings.Removal
 	for rows.Next() {
-		var a readings.Article
-		var tagsJSON string
-		if err := rows.Scan(&a.ID, &a.Title, &a.URL, &tagsJSON, &a.FetchedAt); err != nil {
+		var r readings.Removal
+		var reason string
+		if r.Article, err = scanArticle(rows, &r.At, &reason); err != nil {
 			return nil, err
 		}
+		r.Reason = readings.RemovalReason(reason)
+		removals = append(removals, r)
+	}
+	return removals, rows.Err()
+}
+
+func (s *SQLite) Find(ctx context.Context, ref string) (*readings.Article, error) {
+	query := `
+		SELECT ` + articleColumns + ` FROM articles
+		WHERE removed_at IS NULL AND (replace(id, '-', '') = replace(?, '-', '') OR url = ?)
+		LIMIT 1
+	`
+	rows, err := s.db.QueryContext(ctx, query, ref, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(articles) == 0 {
+		return nil, readings.ErrNotFound
+	}
+	return &articles[0], nil
+}
 
-		if tagsJSON != "" {
-			if err := json.Unmarshal([]byte(tagsJSON), &a.Tags); err != nil {
-				// Log error but continue? Or fail?
-				// For now, empty tags
-				a.Tags = []string{}
+func (s *SQLite) MarkRemoved(ctx context.Context, ids []string, reason readings.RemovalReason) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		UPDATE articles SET removed_at = ?, removed_reason = ?
+		WHERE id = ? AND removed_at IS NULL
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for _, id := range ids {
+		if _, err := stmt.ExecContext(ctx, now, string(reason), id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLite) GetSyncCursor(ctx context.Context, databaseID string) (time.Time, error) {
+	var syncedAt time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT last_synced_at FROM sync_state WHERE database_id = ?`, databaseID).Scan(&syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	return syncedAt, err
+}
+
+func (s *SQLite) SetSyncCursor(ctx context.Context, databaseID string, syncedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO sync_state (database_id, last_synced_at)
+		VALUES (?, ?)
+		ON CONFLICT (database_id) DO UPDATE SET
+			last_synced_at = excluded.last_synced_at
+	`, databaseID, syncedAt.UTC())
+	return err
+}
+
+// tagFilterClause turns a tag filter into AND conditions on articles.
+func tagFilterClause(filter readings.TagFilter) (string, []interface{}) {
+	var where string
+	var args []interface{}
+
+	// matching counts the distinct filter tags an article has.
+	matching := func(tags []string) (string, int) {
+		keys := make(map[string]bool)
+		for _, t := range tags {
+			if key := readings.TagKey(t); !keys[key] {
+				keys[key] = true
+				args = append(args, key)
 			}
 		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		return `(SELECT count(*) FROM article_tags WHERE article_tags.article_id = articles.id AND tag_key IN (` + placeholders + `))`, len(keys)
+	}
+
+	if len(filter.Include) > 0 {
+		count, n := matching(filter.Include)
+		if filter.MatchAll {
+			where += fmt.Sprintf(` AND %s = %d`, count, n)
+		} else {
+			where += fmt.Sprintf(` AND %s > 0`, count)
+		}
+	}
+	if len(filter.Exclude) > 0 {
+		count, _ := matching(filter.Exclude)
+		where += fmt.Sprintf(` AND %s = 0`, count)
+	}
+	return where, args
+}
+
+func (s *SQLite) Search(ctx context.Context, query string, limit int) ([]readings.Article, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, nil
+	}
+
+	// bm25 weights per column: article_id, title, url, tags, notes.
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+articleColumns+`
+		FROM articles_fts
+		JOIN articles ON articles.id = articles_fts.article_id
+		WHERE articles_fts MATCH ? AND articles.removed_at IS NULL
+		ORDER BY bm25(articles_fts, 0, 10.0, 1.0, 5.0, 1.0)
+		LIMIT ?
+	`, match, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanArticles(rows)
+}
+
+// ftsQuery turns free text into an FTS5 query matching every word as a
+// prefix, so partial input finds results and punctuation cannot cause
+// syntax errors.
+func ftsQuery(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+func (s *SQLite) TagCounts(ctx context.Context) ([]readings.TagCount, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT min(article_tags.tag), count(*) FROM article_tags
+		JOIN articles ON articles.id = article_tags.article_id
+		WHERE articles.removed_at IS NULL
+		GROUP BY article_tags.tag_key
+		ORDER BY count(*) DESC, article_tags.tag_key
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []readings.TagCount
+	for rows.Next() {
+		var c readings.TagCount
+		if err := rows.Scan(&c.Tag, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+func scanArticles(rows *sql.Rows) ([]readings.Article, error) {
+	var articles []readings.Article
+	for rows.Next() {
+		a, err := scanArticle(rows)
+		if err != nil {
+			return nil, err
+		}
 		articles = append(articles, a)
 	}
 	return articles, rows.Err()
 }
+
+// scanArticle reads articleColumns, followed by any extra columns into extra.
+func scanArticle(rows *sql.Rows, extra ...interface{}) (readings.Article, error) {
+	var a readings.Article
+	var tagsJSON string
+	var createdAt, surfacedAt sql.NullTime
+	dest := []interface{}{&a.ID, &a.Title, &a.URL, &tagsJSON, &a.FetchedAt, &createdAt, &a.Notes, &a.ReadingMinutes, &a.Priority, &surfacedAt}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return a, err
+	}
+	a.CreatedAt = createdAt.Time
+	a.SurfacedAt = surfacedAt.Time
+
+	if tagsJSON != "" {
+		if err := json.Unmarshal([]byte(tagsJSON), &a.Tags); err != nil {
+			// Log error but continue? Or fail?
+			// For now, empty tags
+			a.Tags = []string{}
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/readings"
+)
+
+func newTestStore(t *testing.T) *SQLite {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), DBFileName))
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSyncCursor(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	cursor, err := store.GetSyncCursor(ctx, "db-1")
+	require.NoError(t, err)
+	assert.True(t, cursor.IsZero())
+
+	first := time.Date(2025, 12, 1, 10, 30, 0, 0, time.UTC)
+	require.NoError(t, store.SetSyncCursor(ctx, "db-1", first))
+	second := first.Add(time.Hour)
+	require.NoError(t, store.SetSyncCursor(ctx, "db-1", second))
+
+	cursor, err = store.GetSyncCursor(ctx, "db-1")
+	require.NoError(t, err)
+	assert.True(t, second.Equal(cursor))
+
+	other, err := store.GetSyncCursor(ctx, "db-2")
+	require.NoError(t, err)
+	assert.True(t, other.IsZero())
+}
+
+func TestMarkRemoved(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	articles := []readings.Article{
+		{ID: "1", Title: "Keep", URL: "https://example.com/1", Tags: []string{"go"}},
+		{ID: "2", Title: "Done", URL: "https://example.com/2", Tags: []string{"go"}},
+	}
+	require.NoError(t, store.SaveUpsert(ctx, articles))
+	require.NoError(t, store.MarkRemoved(ctx, []string{"2"}, readings.RemovedDone))
+
+	all, err := store.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "1", all[0].ID)
+
+	matching, err := store.GetMatching(ctx, readings.TagFilter{Include: []string{"go"}})
+	require.NoError(t, err)
+	require.Len(t, matching, 1)
+	assert.Equal(t, "1", matching[0].ID)
+
+	removed, err := store.Removed(ctx)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "Done", removed[0].Title)
+	assert.Equal(t, readings.RemovedDone, removed[0].Reason)
+	assert.WithinDuration(t, time.Now(), removed[0].At, time.Minute)
+
+	// Saving the article again, e.g. after it is unticked in Notion, revives it.
+	require.NoError(t, store.SaveUpsert(ctx, articles[1:]))
+	all, err = store.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	removed, err = store.Removed(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+}
+
+func TestFind(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{
+		{ID: "2c1f0d3a-7a65-4c1b-9d2e-0f6a8b1c2d3e", Title: "Article", URL: "https://example.com/a"},
+	}))
+
+	for _, ref := range []string{
+		"2c1f0d3a-7a65-4c1b-9d2e-0f6a8b1c2d3e",
+		"2c1f0d3a7a654c1b9d2e0f6a8b1c2d3e",
+		"https://example.com/a",
+	} {
+		article, err := store.Find(ctx, ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, "Article", article.Title)
+	}
+
+	_, err := store.Find(ctx, "https://example.com/missing")
+	assert.ErrorIs(t, err, readings.ErrNotFound)
+
+	require.NoError(t, store.MarkRemoved(ctx, []string{"2c1f0d3a-7a65-4c1b-9d2e-0f6a8b1c2d3e"}, readings.RemovedDone))
+	_, err = store.Find(ctx, "https://example.com/a")
+	assert.ErrorIs(t, err, readings.ErrNotFound)
+}
+
+func TestGetMatching_TagFilter(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{
+		{ID: "1", Title: "Go", URL: "u1", Tags: []string{"Go"}},
+		{ID: "2", Title: "Golang", URL: "u2", Tags: []string{"golang"}},
+		{ID: "3", Title: "Go and Rust", URL: "u3", Tags: []string{"go", "rust"}},
+		{ID: "4", Title: "Untagged", URL: "u4"},
+		{ID: "5", Title: "Écrire", URL: "u5", Tags: []string{"Écriture", "Go"}},
+	}))
+
+	tests := []struct {
+		name     string
+		filter   readings.TagFilter
+		expected []string
+	}{
+		{"no filter", readings.TagFilter{}, []string{"1", "2", "3", "4", "5"}},
+		{"exact and case-insensitive", readings.TagFilter{Include: []string{"GO"}}, []string{"1", "3", "5"}},
+		{"non-ASCII", readings.TagFilter{Include: []string{"éCRITURE"}}, []string{"5"}},
+		{"all of non-ASCII", readings.TagFilter{Include: []string{"go", "ÉCRITURE", "écriture"}, MatchAll: true}, []string{"5"}},
+		{"any of", readings.TagFilter{Include: []string{"golang", "rust"}}, []string{"2", "3"}},
+		{"exclude", readings.TagFilter{Exclude: []string{"rust"}}, []string{"1", "2", "4", "5"}},
+		{"include and exclude", readings.TagFilter{Include: []string{"go"}, Exclude: []string{"rust"}}, []string{"1", "5"}},
+		{"all of", readings.TagFilter{Include: []string{"go", "RUST"}, MatchAll: true}, []string{"3"}},
+		{"all of with duplicates", readings.TagFilter{Include: []string{"go", "Go"}, MatchAll: true}, []string{"1", "3", "5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := store.GetMatching(ctx, tt.filter)
+			require.NoError(t, err)
+			var ids []string
+			for _, a := range articles {
+				ids = append(ids, a.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestSaveUpsert_ReplacesTags(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	article := readings.Article{ID: "1", Title: "A", URL: "u1", Tags: []string{"go", "rust"}}
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{article}))
+	article.Tags = []string{"zig"}
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{article}))
+
+	found, err := store.GetMatching(ctx, readings.TagFilter{Include: []string{"go"}})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	found, err = store.GetMatching(ctx, readings.TagFilter{Include: []string{"zig"}})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+}
+
+func TestSaveUpsert_Metadata(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	created := time.Date(2025, 11, 3, 8, 15, 0, 0, time.UTC)
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{
+		{ID: "1", Title: "With metadata", URL: "https://example.com/1", CreatedAt: created, Notes: "Skim it", ReadingMinutes: 12},
+		{ID: "2", Title: "Without", URL: "https://example.com/2"},
+	}))
+
+	a, err := store.Find(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, created.Equal(a.CreatedAt))
+	assert.Equal(t, "Skim it", a.Notes)
+	assert.Equal(t, 12, a.ReadingMinutes)
+
+	b, err := store.Find(ctx, "2")
+	require.NoError(t, err)
+	assert.True(t, b.CreatedAt.IsZero())
+	assert.Empty(t, b.Notes)
+	assert.Zero(t, b.ReadingMinutes)
+}
+
+func TestSaveUpsert_Priority(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	article := readings.Article{ID: "1", Title: "A", URL: "u1", Priority: 3}
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{article}))
+	a, err := store.Find(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, a.Priority)
+
+	// A sync that brings a new priority replaces the old one.
+	article.Priority = 1
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{article}))
+	all, err := store.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, 1, all[0].Priority)
+}
+
+func TestTagCounts(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{
+		{ID: "1", Title: "A", URL: "u1", Tags: []string{"Go"}},
+		{ID: "2", Title: "B", URL: "u2", Tags: []string{"go", "rust"}},
+		{ID: "3", Title: "C", URL: "u3", Tags: []string{"go"}},
+		{ID: "4", Title: "D", URL: "u4", Tags: []string{"zig"}},
+	}))
+	require.NoError(t, store.MarkRemoved(ctx, []string{"4"}, readings.RemovedDone))
+
+	counts, err := store.TagCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []readings.TagCount{
+		{Tag: "Go", Count: 3},
+		{Tag: "rust", Count: 1},
+	}, counts)
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{
+		{ID: "1", Title: "Understanding Go Generics", URL: "https://go.dev/blog/intro-generics", Tags: []string{"go"}},
+		{ID: "2", Title: "Rust ownership explained", URL: "https://example.com/rust", Tags: []string{"rust"}},
+		{ID: "3", Title: "A café guide", URL: "https://example.com/coffee", Tags: []string{"generics", "misc"}},
+		{ID: "4", Title: "Generic programming in C++", URL: "https://example.com/cpp", Tags: []string{"c++"}},
+		{ID: "5", Title: "Weekly digest", URL: "https://example.com/digest", Notes: "Skim the section on borrowck"},
+	}))
+
+	ids := func(query string) []string {
+		t.Helper()
+		articles, err := store.Search(ctx, query, 10)
+		require.NoError(t, err)
+		var result []string
+		for _, a := range articles {
+			result = append(result, a.ID)
+		}
+		return result
+	}
+
+	// Title matches rank above tag-only matches.
+	assert.Equal(t, []string{"1", "4", "3"}, ids("generic"))
+	assert.Equal(t, []string{"1"}, ids("go generics"))
+	assert.Equal(t, []string{"1"}, ids("go.dev"))
+	assert.Equal(t, []string{"3"}, ids("cafe"))
+	assert.Equal(t, []string{"5"}, ids("borrowck"))
+	// Quotes and operators are treated as text, not query syntax.
+	assert.Equal(t, []string{"4"}, ids(`"generic programming`))
+	assert.Equal(t, []string{"4"}, ids("C++ IN"))
+	assert.Empty(t, ids("   "))
+
+	// The index follows updates and removals.
+	require.NoError(t, store.SaveUpsert(ctx, []readings.Article{
+		{ID: "2", Title: "Rust generics", URL: "https://example.com/rust", Tags: []string{"rust"}},
+	}))
+	require.NoError(t, store.MarkRemoved(ctx, []string{"4"}, readings.RemovedDone))
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, ids("generic"))
+	assert.Empty(t, ids("ownership"))
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"productivity.go/internal/readings"
+)
+
+func (s *SQLite) SaveWeek(ctx context.Context, week readings.Week) error {
+	ids := week.ReadingListIDs
+	if ids == nil {
+		ids = []string{}
+	}
+	listJSON, err := json.Marshal(ids)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reading list of week %s: %w", week.ID, err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO weeks (id, starts_at, ends_at, reading_list)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			starts_at = excluded.starts_at,
+			ends_at = excluded.ends_at,
+			reading_list = excluded.reading_list
+	`, week.ID, week.Start.UTC(), week.End.UTC(), string(listJSON))
+	return err
+}
+
+func (s *SQLite) WeekAt(ctx context.Context, t time.Time) (*readings.Week, error) {
+	// Few weeks are stored, and stored times do not compare reliably as
+	// text, so spans are checked here rather than in SQL.
+	weeks, err := s.weeksWhere(ctx, func(w readings.Week) bool { return w.Contains(t) })
+	if err != nil {
+		return nil, err
+	}
+	if len(weeks) == 0 {
+		return nil, readings.ErrNotFound
+	}
+	return &weeks[len(weeks)-1], nil
+}
+
+func (s *SQLite) Weeks(ctx context.Context, from, to time.Time) ([]readings.Week, error) {
+	return s.weeksWhere(ctx, func(w readings.Week) bool {
+		return !w.End.Before(from) && !w.Start.After(to)
+	})
+}
+
+// weeksWhere returns the stored weeks keep accepts, earliest first.
+func (s *SQLite) weeksWhere(ctx context.Context, keep func(readings.Week) bool) ([]readings.Week, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, starts_at, ends_at, reading_list FROM weeks`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weeks []readings.Week
+	for rows.Next() {
+		var w readings.Week
+		var listJSON string
+		if err := rows.Scan(&w.ID, &w.Start, &w.End, &listJSON); err != nil {
+			return nil, err
+		}
+		if !keep(w) {
+			continue
+		}
+		if err := json.Unmarshal([]byte(listJSON), &w.ReadingListIDs); err != nil {
+			return nil, fmt.Errorf("failed to parse reading list of week %s: %w", w.ID, err)
+		}
+		weeks = append(weeks, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(weeks, func(i, j int) bool { return weeks[i].Start.Before(weeks[j].Start) })
+	return weeks, nil
+}
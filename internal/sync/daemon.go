@@ -0,0 +1,63 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Every calls fn right away and then every interval until ctx is done. It
+// never interrupts fn; cancelling ctx only stops further calls.
+func Every(ctx context.Context, interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fn()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// UnitName is the base name of the systemd units written by SystemdUnits.
+const UnitName = "readings-sync"
+
+// SystemdUnits returns a oneshot service running 'exe sync' and a timer
+// starting it every interval.
+func SystemdUnits(exe string, interval time.Duration) (service, timer string) {
+	service = fmt.Sprintf(`[Unit]
+Description=Sync the readings cache with Notion
+
+[Service]
+Type=oneshot
+ExecStart=%s sync
+`, systemdQuote(exe))
+
+	timer = fmt.Sprintf(`[Unit]
+Description=Sync the readings cache with Notion every %s
+
+[Timer]
+OnBootSec=1min
+OnUnitActiveSec=%ds
+Unit=%s.service
+
+[Install]
+WantedBy=timers.target
+`, interval, int(interval.Round(time.Second)/time.Second), UnitName)
+
+	return service, timer
+}
+
+// systemdQuote quotes a path for an Exec line if it contains spaces or
+// characters systemd would interpret.
+func systemdQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\"'\\$%;") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", "$$", "%", "%%")
+	return `"` + r.Replace(s) + `"`
+}
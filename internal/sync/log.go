@@ -0,0 +1,26 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+)
+
+// MaxLogSize is the size past which the sync log is rotated.
+const MaxLogSize = 1 << 20
+
+// OpenLog opens the sync log for appending. A log larger than MaxLogSize is
+// first moved to path.1, replacing the previous one, so at most two logs are
+// kept.
+func OpenLog(path string) (*os.File, error) {
+	if info, err := os.Stat(path); err == nil && info.Size() > MaxLogSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return nil, fmt.Errorf("failed to rotate sync log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sync log: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,32 @@
+package sync
+
+import (
+	"path/filepath"
+
+	"productivity.go/internal/storage"
+)
+
+// Paths locates the files shared by sync processes.
+type Paths struct {
+	Lock   string // Held while a sync runs
+	Log    string // Appended to by every sync
+	Status string // Outcome of the last sync
+}
+
+// DefaultPaths keeps the sync files next to the article cache.
+func DefaultPaths() (Paths, error) {
+	dbPath, err := storage.DefaultPath()
+	if err != nil {
+		return Paths{}, err
+	}
+	return PathsIn(filepath.Dir(dbPath)), nil
+}
+
+// PathsIn returns the sync files in dir.
+func PathsIn(dir string) Paths {
+	return Paths{
+		Lock:   filepath.Join(dir, "sync.lock"),
+		Log:    filepath.Join(dir, "sync.log"),
+		Status: filepath.Join(dir, "last-sync.json"),
+	}
+}
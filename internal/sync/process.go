@@ -18,7 +18,7 @@ func TriggerBackgroundSync() error {
 		Setsid: true, // Detach from terminal
 	}
 
-	// We don't care about output
+	// The sync writes its own log; see Run.
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 	cmd.Stdin = nil
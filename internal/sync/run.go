@@ -0,0 +1,66 @@
+package sync
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"productivity.go/internal/readings"
+)
+
+// Run calls sync while holding the sync lock, logs the outcome and saves it
+// as the last status. If another sync is running it returns ErrLocked
+// without calling sync, and the last status is left as it is.
+func Run(paths Paths, full bool, sync func() (readings.SyncResult, error)) (readings.SyncResult, error) {
+	if err := os.MkdirAll(filepath.Dir(paths.Lock), 0755); err != nil {
+		return readings.SyncResult{}, fmt.Errorf("failed to create sync directory: %w", err)
+	}
+
+	logFile, err := OpenLog(paths.Log)
+	if err != nil {
+		return readings.SyncResult{}, err
+	}
+	defer logFile.Close()
+	logger := log.New(logFile, fmt.Sprintf("[%d] ", os.Getpid()), log.LstdFlags|log.Lmsgprefix)
+
+	lock, err := Acquire(paths.Lock)
+	if err != nil {
+		if pid, running, _ := Holder(paths.Lock); running && pid > 0 {
+			logger.Printf("sync skipped: %v (pid %d)", err, pid)
+		} else {
+			logger.Printf("sync skipped: %v", err)
+		}
+		return readings.SyncResult{}, err
+	}
+	defer lock.Release()
+
+	kind := "incremental"
+	if full {
+		kind = "full"
+	}
+	logger.Printf("%s sync started", kind)
+
+	status := Status{StartedAt: time.Now(), Full: full}
+	result, err := sync()
+	status.FinishedAt = time.Now()
+	status.SyncResult = result
+
+	took := status.Duration().Round(time.Millisecond)
+	if err != nil {
+		status.Error = err.Error()
+		logger.Printf("sync failed after %s: %v", took, err)
+	} else {
+		logger.Printf("sync finished in %s: pushed %d, fetched %d, removed %d",
+			took, result.Pushed, result.Fetched, result.Removed)
+		if result.PushError != "" {
+			logger.Print(result.PushError)
+		}
+	}
+
+	if saveErr := SaveStatus(paths.Status, status); saveErr != nil {
+		logger.Print(saveErr)
+	}
+	return result, err
+}
@@ -0,0 +1,72 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"productivity.go/internal/readings"
+)
+
+// Status is the outcome of a sync, saved so that failures of background
+// syncs are not lost.
+type Status struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Full       bool      `json:"full"`
+	readings.SyncResult
+	Error string `json:"error,omitempty"`
+}
+
+// Duration returns how long the sync took.
+func (s Status) Duration() time.Duration {
+	return s.FinishedAt.Sub(s.StartedAt)
+}
+
+// SaveStatus replaces the status file at path.
+func SaveStatus(path string, status Status) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see half a status.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".last-sync-*")
+	if err != nil {
+		return fmt.Errorf("failed to save sync status: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save sync status: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save sync status: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save sync status: %w", err)
+	}
+	return nil
+}
+
+// LoadStatus reads the status file at path. It returns nil if no sync has
+// finished yet.
+func LoadStatus(path string) (*Status, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync status: %w", err)
+	}
+
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse sync status %s: %w", path, err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,147 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"productivity.go/internal/readings"
+)
+
+func TestAcquire_ExcludesOtherSyncs(t *testing.T) {
+	paths := PathsIn(t.TempDir())
+
+	_, running, err := Holder(paths.Lock)
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	lock, err := Acquire(paths.Lock)
+	require.NoError(t, err)
+
+	// flock locks belong to the open file, so a second open conflicts even
+	// within one process.
+	_, err = Acquire(paths.Lock)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	pid, running, err := Holder(paths.Lock)
+	require.NoError(t, err)
+	assert.True(t, running)
+	assert.Equal(t, os.Getpid(), pid)
+
+	require.NoError(t, lock.Release())
+	_, running, err = Holder(paths.Lock)
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	lock, err = Acquire(paths.Lock)
+	require.NoError(t, err)
+	lock.Release()
+}
+
+func TestOpenLog_Rotates(t *testing.T) {
+	paths := PathsIn(t.TempDir())
+	require.NoError(t, os.WriteFile(paths.Log, []byte(strings.Repeat("x", MaxLogSize+1)), 0644))
+
+	f, err := OpenLog(paths.Log)
+	require.NoError(t, err)
+	f.WriteString("fresh\n")
+	f.Close()
+
+	data, err := os.ReadFile(paths.Log)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh\n", string(data))
+
+	old, err := os.Stat(paths.Log + ".1")
+	require.NoError(t, err)
+	assert.EqualValues(t, MaxLogSize+1, old.Size())
+}
+
+func TestRun_SavesStatus(t *testing.T) {
+	paths := PathsIn(t.TempDir())
+
+	status, err := LoadStatus(paths.Status)
+	require.NoError(t, err)
+	assert.Nil(t, status)
+
+	want := readings.SyncResult{Pushed: 1, Fetched: 3, Removed: 2}
+	result, err := Run(paths, true, func() (readings.SyncResult, error) { return want, nil })
+	require.NoError(t, err)
+	assert.Equal(t, want, result)
+
+	status, err = LoadStatus(paths.Status)
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	assert.True(t, status.Full)
+	assert.Equal(t, want, status.SyncResult)
+	assert.Empty(t, status.Error)
+	assert.False(t, status.FinishedAt.Before(status.StartedAt))
+
+	_, err = Run(paths, false, func() (readings.SyncResult, error) {
+		return readings.SyncResult{Pushed: 1}, assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+
+	status, err = LoadStatus(paths.Status)
+	require.NoError(t, err)
+	assert.Equal(t, assert.AnError.Error(), status.Error)
+	assert.Equal(t, 1, status.Pushed)
+
+	log, err := os.ReadFile(paths.Log)
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "full sync started")
+	assert.Contains(t, string(log), "sync finished in")
+	assert.Contains(t, string(log), "pushed 1, fetched 3, removed 2")
+	assert.Contains(t, string(log), "sync failed after")
+}
+
+func TestRun_SkipsWhileLocked(t *testing.T) {
+	paths := PathsIn(t.TempDir())
+	lock, err := Acquire(paths.Lock)
+	require.NoError(t, err)
+	defer lock.Release()
+
+	called := false
+	_, err = Run(paths, false, func() (readings.SyncResult, error) {
+		called = true
+		return readings.SyncResult{}, nil
+	})
+	assert.ErrorIs(t, err, ErrLocked)
+	assert.False(t, called)
+
+	status, err := LoadStatus(paths.Status)
+	require.NoError(t, err)
+	assert.Nil(t, status, "A skipped sync keeps the last status")
+
+	log, err := os.ReadFile(paths.Log)
+	require.NoError(t, err)
+	assert.Contains(t, string(log), "sync skipped: another sync is already running")
+}
+
+func TestEvery_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	Every(ctx, time.Millisecond, func() {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+	})
+	assert.Equal(t, 3, calls)
+}
+
+func TestSystemdUnits(t *testing.T) {
+	service, timer := SystemdUnits("/home/me/My Tools/readings", 15*time.Minute)
+
+	assert.Contains(t, service, "Type=oneshot\n")
+	assert.Contains(t, service, `ExecStart="/home/me/My Tools/readings" sync`+"\n")
+	assert.Contains(t, timer, "OnUnitActiveSec=900s\n")
+	assert.Contains(t, timer, "Unit=readings-sync.service\n")
+	assert.Contains(t, timer, "WantedBy=timers.target\n")
+
+	service, _ = SystemdUnits("/usr/bin/readings", time.Hour)
+	assert.Contains(t, service, "ExecStart=/usr/bin/readings sync\n")
+}
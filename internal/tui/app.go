@@ -7,11 +7,25 @@ tea "github.com/charmbracelet/bubbletea"
 "productivity.go/internal/readings"
 )
 
-func Start(service *readings.Service) error {
-	model, err := InitTUI(service)
+// Options configures the TUI.
+type Options struct {
+	Filter     readings.TagFilter   // Applied on launch
+	Sync       SyncFunc             // Run in the background on launch if set
+	WeeklyGoal int                  // Articles to plan per week; 0 hides the goal
+	Plan       readings.PlanOptions // Used by the week view's plan action
+}
+
+// Start runs the TUI. If opts.Sync is set, the list is refreshed when the
+// sync finishes.
+func Start(service *readings.Service, opts Options) error {
+	model, err := InitTUI(service, opts.Filter)
 	if err != nil {
 		return fmt.Errorf("failed to initialize TUI: %w", err)
 	}
+	model.syncFn = opts.Sync
+	model.syncing = opts.Sync != nil
+	model.weeklyGoal = opts.WeeklyGoal
+	model.planOpts = opts.Plan
 
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
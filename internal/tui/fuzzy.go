@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+
+	"productivity.go/internal/readings"
+)
+
+// Scoring for fuzzyMatch. Consecutive and word-start matches are what make
+// a result feel right when typing abbreviations like "gogen".
+const (
+	scoreMatch       = 1
+	bonusConsecutive = 5
+	bonusWordStart   = 8
+	penaltyGap       = 1
+)
+
+// fuzzyMatch reports whether every rune of pattern appears in text in order,
+// ignoring case. It returns a score rewarding consecutive runs and matches
+// at word starts, and the rune indices of the matched characters.
+func fuzzyMatch(pattern, text string) (int, []int, bool) {
+	// Runes are folded one by one so indices into t are indices into text;
+	// strings.ToLower can change the rune count (e.g. U+0130).
+	p := lowerRunes(pattern)
+	t := lowerRunes(text)
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	bestScore := -1
+	var best []int
+	// Try every occurrence of the first rune as the start, so "gen" in
+	// "big green generics" finds "generics" instead of scattering over
+	// "big green".
+	for start := range t {
+		if t[start] != p[0] {
+			continue
+		}
+		score, positions, ok := matchFrom(p, t, start)
+		if ok && score > bestScore {
+			bestScore, best = score, positions
+		}
+	}
+
+	if best == nil {
+		return 0, nil, false
+	}
+	return bestScore, best, true
+}
+
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// matchFrom greedily matches p in t starting at index start.
+func matchFrom(p, t []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(p))
+	score := 0
+	i := start
+	for _, r := range p {
+		for i < len(t) && t[i] != r {
+			i++
+		}
+		if i == len(t) {
+			return 0, nil, false
+		}
+
+		score += scoreMatch
+		if i == 0 || !isWordRune(t[i-1]) {
+			score += bonusWordStart
+		}
+		if n := len(positions); n > 0 {
+			if positions[n-1] == i-1 {
+				score += bonusConsecutive
+			} else {
+				score -= penaltyGap
+			}
+		}
+		positions = append(positions, i)
+		i++
+	}
+	return score, positions, true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// matchArticle matches every whitespace-separated term of query against the
+// article's title, URL host and tags. Title matches weigh double and their
+// positions are returned for highlighting.
+func matchArticle(a readings.Article, terms []string) (int, []int, bool) {
+	host := urlHost(a.URL)
+
+	total := 0
+	var highlights []int
+	for _, term := range terms {
+		best := -1
+		var bestPositions []int
+
+		if score, positions, ok := fuzzyMatch(term, a.Title); ok {
+			best, bestPositions = 2*score, positions
+		}
+		if score, _, ok := fuzzyMatch(term, host); ok && score > best {
+			best, bestPositions = score, nil
+		}
+		for _, tag := range a.Tags {
+			if score, _, ok := fuzzyMatch(term, tag); ok && score > best {
+				best, bestPositions = score, nil
+			}
+		}
+
+		if best < 0 {
+			return 0, nil, false
+		}
+		total += best
+		highlights = append(highlights, bestPositions...)
+	}
+	return total, highlights, true
+}
+
+// urlHost returns the host of rawURL without a leading "www.".
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"productivity.go/internal/readings"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"gen", "Go Generics", true, []int{3, 4, 5}},
+		{"GG", "go generics", true, []int{0, 3}},
+		{"gen", "big green generics", true, []int{10, 11, 12}},
+		{"xyz", "Go Generics", false, nil},
+		{"sg", "go", false, nil},
+		{"", "anything", true, nil},
+		{"é", "Café", true, []int{3}},
+		{"s", "İstanbul", true, []int{1}}, // Lowercases to two runes as a string
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.text, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch(tt.pattern, tt.text)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.positions, positions)
+		})
+	}
+}
+
+func TestFuzzyMatch_PrefersConsecutiveWordStarts(t *testing.T) {
+	word, _, _ := fuzzyMatch("gen", "Go Generics")
+	scattered, _, _ := fuzzyMatch("gen", "big green onion")
+	assert.Greater(t, word, scattered)
+}
+
+func TestMatchArticle(t *testing.T) {
+	article := readings.Article{
+		Title: "Understanding Generics",
+		URL:   "https://www.go.dev/blog/intro",
+		Tags:  []string{"golang"},
+	}
+
+	_, positions, ok := matchArticle(article, []string{"gen"})
+	assert.True(t, ok)
+	assert.Equal(t, []int{14, 15, 16}, positions)
+
+	// Host and tag matches do not highlight the title.
+	_, positions, ok = matchArticle(article, []string{"godev", "lang"})
+	assert.True(t, ok)
+	assert.Empty(t, positions)
+
+	_, _, ok = matchArticle(article, []string{"gen", "rust"})
+	assert.False(t, ok)
+}
+
+func TestHighlight(t *testing.T) {
+	plain := lipgloss.NewStyle()
+	assert.Equal(t, "Go Generics", highlight("Go Generics", []int{3, 4, 5}, plain, plain))
+}
@@ -2,9 +2,11 @@ package tui
 
 import (
 	"context"
-	"math/rand"
 	"sort"
+	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"productivity.go/internal/readings"
 )
@@ -13,9 +15,11 @@ import (
 type ViewState int
 
 const (
-ViewList ViewState = iota
-ViewDetail
-ViewFilter
+	ViewList ViewState = iota
+	ViewDetail
+	ViewFilter
+	ViewSearch
+	ViewWeek
 )
 
 // Model holds the application state.
@@ -25,38 +29,149 @@ type Model struct {
 	filteredArticles   []readings.Article
 	tags               []string
 	selectedTags       map[string]bool
+	excludedTags       map[string]bool
+	matchAllTags       bool            // Require every selected tag instead of any
 	backupSelectedTags map[string]bool // To restore on Cancel
+	backupExcludedTags map[string]bool
+	backupMatchAllTags bool
+
+	// Search
+	searchInput       textinput.Model
+	searchQuery       string           // Live query, empty when not searching
+	searchMatches     map[string][]int // Matched title runes by article ID, for highlighting
+	backupSearchQuery string           // To restore on Cancel
+
+	// Planned week, the current one unless another is picked in the week view
+	weekAt     time.Time       // A time within the week; zero for now
+	weekIDs    map[string]bool // Articles in the week's reading list; nil until loaded
+	weekList   []string        // The reading list in order
+	weekStart  time.Time
+	weekEnd    time.Time
+	weekErr    error // Why the week could not be loaded
+	weekCursor int   // Selected item in the week view
+	weeklyGoal int   // Articles to plan per week; 0 for no goal
+
+	// Reordering in the week view, saved one reorder at a time
+	moving      bool // A reorder is being saved
+	movePending bool // The list was reordered again meanwhile
+
+	// Plan proposed in the week view, waiting to be accepted
+	planOpts readings.PlanOptions
+	proposal *readings.Plan
+
+	// Sync started on launch
+	syncing bool
+	spinner spinner.Model
 
 	// State
-	view         ViewState
-	cursor       int // Index of selected item in the current list
-	scrollOffset int // For scrolling long lists
-	width        int
-	height       int
-	inputBuffer  string // For Vim-style numeric commands
+	view          ViewState
+	cursor        int // Index of selected item in the current list
+	scrollOffset  int // For scrolling long lists
+	width         int
+	height        int
+	inputBuffer   string // For Vim-style numeric commands
 	statusMessage string
 
 	// Services
-	svc *readings.Service
+	svc    *readings.Service
+	syncFn SyncFunc // Nil to only show the cache
 }
 
+// SyncFunc pulls changes from Notion into the cache the TUI reads.
+type SyncFunc func(ctx context.Context) (readings.SyncResult, error)
+
 type ClearStatusMsg struct{}
 type StatusMsg string
 
+// ArticleDoneMsg reports that an article was marked as done.
+type ArticleDoneMsg struct {
+	ID     string
+	Queued int // Changes not yet in Notion
+}
+
+// WeekLoadedMsg carries a week's reading list, fetched on launch for the
+// current week and again when another week is picked.
+type WeekLoadedMsg struct {
+	At          time.Time // The weekAt it was loaded for
+	ReadingList []string
+	Start, End  time.Time
+	Err         error
+}
+
+// WeekToggledMsg reports that an article was added to or removed from the
+// planned week.
+type WeekToggledMsg struct {
+	At          time.Time
+	Added       bool
+	ReadingList []string
+	Queued      int // Changes not yet in Notion
+}
+
+// WeekMovedMsg reports that an article was moved within the planned week's
+// reading list.
+type WeekMovedMsg struct {
+	At          time.Time
+	ReadingList []string
+	Queued      int // Changes not yet in Notion
+	Err         error
+}
+
+// PlanProposedMsg carries a plan for the week shown in the week view.
+type PlanProposedMsg struct {
+	At   time.Time
+	Plan readings.Plan
+	Err  error
+}
 
-// InitTUI initializes the TUI model with data.
-func InitTUI(svc *readings.Service) (Model, error) {
+// PlanAppliedMsg reports that a proposed plan was added to the reading list.
+type PlanAppliedMsg struct {
+	At          time.Time
+	Added       int
+	ReadingList []string
+	Queued      int // Changes not yet in Notion
+}
+
+// SyncedMsg reports that the sync started on launch finished.
+type SyncedMsg struct {
+	Err      error
+	Reloaded bool               // Whether the cache could be read afterwards
+	Articles []readings.Article // The cache after the sync
+}
+
+// InitTUI initializes the TUI model with data, pre-applying the tag filter.
+func InitTUI(svc *readings.Service, filter readings.TagFilter) (Model, error) {
 	articles, err := svc.GetAll(context.Background())
 	if err != nil {
 		return Model{}, err
 	}
 
-	// Shuffle articles
-	rand.Shuffle(len(articles), func(i, j int) {
-		articles[i], articles[j] = articles[j], articles[i]
-	})
+	// Most wanted first, as the service's strategy sees it
+	svc.Order(articles)
+
+	m := Model{
+		articles:         articles,
+		filteredArticles: articles, // Initially show all
+		tags:             uniqueTags(articles),
+		selectedTags:     make(map[string]bool),
+		excludedTags:     make(map[string]bool),
+		matchAllTags:     filter.MatchAll,
+		view:             ViewList,
+		spinner:          spinner.New(spinner.WithSpinner(spinner.MiniDot)),
+		svc:              svc,
+	}
+	for _, t := range filter.Include {
+		m.selectedTags[m.canonicalTag(t)] = true
+	}
+	for _, t := range filter.Exclude {
+		m.excludedTags[m.canonicalTag(t)] = true
+	}
+	m.applyFilter()
+
+	return m, nil
+}
 
-	// Extract unique tags
+// uniqueTags returns the sorted tags used by articles.
+func uniqueTags(articles []readings.Article) []string {
 	tagMap := make(map[string]bool)
 	for _, a := range articles {
 		for _, t := range a.Tags {
@@ -68,17 +183,29 @@ func InitTUI(svc *readings.Service) (Model, error) {
 		tags = append(tags, t)
 	}
 	sort.Strings(tags)
+	return tags
+}
 
-	return Model{
-		articles:         articles,
-		filteredArticles: articles, // Initially show all
-		tags:             tags,
-		selectedTags:     make(map[string]bool),
-		view:             ViewList,
-		svc:              svc,
-	}, nil
+// canonicalTag returns the known tag equal to tag ignoring case, so tags
+// given on the command line show up as selected in the filter view.
+func (m Model) canonicalTag(tag string) string {
+	for _, t := range m.tags {
+		if readings.TagKey(t) == readings.TagKey(tag) {
+			return t
+		}
+	}
+	return tag
 }
 
+// Init loads the current week and starts a sync so articles added in Notion
+// since the last session show up without restarting.
 func (m Model) Init() tea.Cmd {
-	return nil
+	var cmds []tea.Cmd
+	if m.svc != nil {
+		cmds = append(cmds, m.loadWeek())
+	}
+	if m.syncFn != nil {
+		cmds = append(cmds, m.spinner.Tick, m.startSync())
+	}
+	return tea.Batch(cmds...)
 }
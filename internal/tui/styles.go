@@ -6,10 +6,13 @@ type Styles struct {
 	Title        lipgloss.Style
 	Item         lipgloss.Style
 	SelectedItem lipgloss.Style
+	Match        lipgloss.Style
 	FilterTitle  lipgloss.Style
 	FilterItem   lipgloss.Style
 	DetailTitle  lipgloss.Style
+	DetailLabel  lipgloss.Style
 	DetailInfo   lipgloss.Style
+	DetailNotes  lipgloss.Style
 	HelpBar      lipgloss.Style
 	HelpKey      lipgloss.Style
 	HelpDesc     lipgloss.Style
@@ -28,6 +31,9 @@ func DefaultStyles() Styles {
 		SelectedItem: lipgloss.NewStyle().
 			PaddingLeft(2).
 			Foreground(lipgloss.Color("205")),
+		Match: lipgloss.NewStyle().
+			Bold(true).
+			Underline(true),
 		FilterTitle: lipgloss.NewStyle().
 			Bold(true).
 			Foreground(lipgloss.Color("#FAFAFA")).
@@ -39,8 +45,13 @@ func DefaultStyles() Styles {
 			Bold(true).
 			Foreground(lipgloss.Color("205")).
 			MarginBottom(1),
+		DetailLabel: lipgloss.NewStyle().
+			Bold(true).
+			Width(14),
 		DetailInfo: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")),
+		DetailNotes: lipgloss.NewStyle().
+			Italic(true),
 		HelpBar: lipgloss.NewStyle().
 			Width(100). // Will be updated dynamically if needed, or just let it flow
 			Foreground(lipgloss.Color("#A8A8A8")).
@@ -2,14 +2,22 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"runtime"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"productivity.go/internal/readings"
+	"productivity.go/internal/sync"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -17,7 +25,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
-			if m.view != ViewFilter { 
+			if msg.String() == "q" && (m.view == ViewSearch || m.view == ViewDetail || m.view == ViewWeek) {
+				break // Typed into the search box, or back to the list
+			}
+			if m.view != ViewFilter {
 				return m, tea.Quit
 			}
 		}
@@ -32,6 +43,90 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ClearStatusMsg:
 		m.statusMessage = ""
 		return m, nil
+	case WeekLoadedMsg:
+		if !msg.At.Equal(m.weekAt) {
+			return m, nil // Another week was picked meanwhile
+		}
+		if msg.Err != nil {
+			m.weekErr = msg.Err
+			return m, func() tea.Msg { return StatusMsg(fmt.Sprintf("Error: %v", msg.Err)) }
+		}
+		m.setWeek(msg.ReadingList)
+		m.weekStart, m.weekEnd = msg.Start, msg.End
+		return m, nil
+	case WeekMovedMsg:
+		m.moving = false
+		if m.movePending {
+			// The list on screen is ahead of the saved one; save it as is.
+			m.movePending = false
+			return m, m.reorderWeek()
+		}
+		if msg.Err != nil {
+			return m, func() tea.Msg { return StatusMsg(fmt.Sprintf("Error: %v", msg.Err)) }
+		}
+		if msg.At.Equal(m.weekAt) {
+			m.setWeek(msg.ReadingList)
+		}
+		if msg.Queued == 0 {
+			return m, nil
+		}
+		return m, func() tea.Msg { return StatusMsg("Moved" + queuedNote(msg.Queued)) }
+	case PlanProposedMsg:
+		if !msg.At.Equal(m.weekAt) || m.view != ViewWeek {
+			return m, nil
+		}
+		if msg.Err != nil {
+			return m, func() tea.Msg { return StatusMsg(fmt.Sprintf("Error: %v", msg.Err)) }
+		}
+		if len(msg.Plan.Proposed) == 0 {
+			return m, func() tea.Msg { return StatusMsg("Nothing to add to the plan") }
+		}
+		m.proposal = &msg.Plan
+		return m, nil
+	case PlanAppliedMsg:
+		if msg.At.Equal(m.weekAt) {
+			m.setWeek(msg.ReadingList)
+		}
+		status := fmt.Sprintf("Added %d article(s) to the reading list", msg.Added)
+		return m, func() tea.Msg { return StatusMsg(status + queuedNote(msg.Queued)) }
+	case WeekToggledMsg:
+		if msg.At.Equal(m.weekAt) {
+			m.setWeek(msg.ReadingList)
+		}
+		status := "Removed from reading list"
+		if msg.Added {
+			status = "Added to reading list"
+		}
+		return m, func() tea.Msg { return StatusMsg(status + queuedNote(msg.Queued)) }
+	case spinner.TickMsg:
+		if !m.syncing {
+			return m, nil // Stop ticking
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case SyncedMsg:
+		m.syncing = false
+		var added, removed int
+		if msg.Reloaded {
+			added, removed = m.mergeArticles(msg.Articles)
+		}
+		status := syncStatus(added, removed)
+		switch {
+		case errors.Is(msg.Err, sync.ErrLocked):
+			// The daemon or a background sync is doing the work; the
+			// reload above shows what it has saved so far.
+			status = "Another sync is running; skipped"
+		case msg.Err != nil:
+			status = fmt.Sprintf("Sync failed: %v", msg.Err)
+		}
+		return m, func() tea.Msg { return StatusMsg(status) }
+	case ArticleDoneMsg:
+		m.removeArticle(msg.ID)
+		if m.view == ViewDetail {
+			m.view = ViewList
+		}
+		return m, func() tea.Msg { return StatusMsg("Marked as done" + queuedNote(msg.Queued)) }
 	}
 
 	switch m.view {
@@ -41,6 +136,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateDetail(msg)
 	case ViewFilter:
 		return m.updateFilter(msg)
+	case ViewSearch:
+		return m.updateSearch(msg)
+	case ViewWeek:
+		return m.updateWeek(msg)
 	}
 
 	return m, nil
@@ -91,185 +190,743 @@ func (m Model) updateList(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "g":
 			if m.inputBuffer == "" {
 				// Wait for second 'g' or handle 'gg' logic if we had a key buffer for commands
-				// But here we only buffer numbers. 
+				// But here we only buffer numbers.
 				// Standard Vim 'gg' is tricky without a command buffer.
 				// Let's assume 'g' goes to top if no number, or line N if number?
-// Actually 'gg' is go to top. 'G' is go to bottom.
-// '10G' is go to line 10.
-// '10gg' is go to line 10.
-
-// Since we don't have a command buffer for 'gg', let's just implement 'G' for now.
-// Or we can treat 'g' as 'home' if no buffer?
-m.cursor = 0
-m.scrollOffset = 0
-} else {
-// If number provided, go to that line (1-based)
-target := count - 1
-if target < 0 { target = 0 }
-if target >= len(m.filteredArticles) { target = len(m.filteredArticles) - 1 }
-m.cursor = target
-// Adjust scroll
-if m.cursor < m.scrollOffset || m.cursor >= m.scrollOffset+(m.height-4) {
-m.scrollOffset = m.cursor - (m.height-4)/2
-if m.scrollOffset < 0 { m.scrollOffset = 0 }
-}
-}
-m.inputBuffer = ""
-case "G":
-if m.inputBuffer == "" {
-// Go to bottom
-m.cursor = len(m.filteredArticles) - 1
-} else {
-// Go to line N
-target := count - 1
-if target < 0 { target = 0 }
-if target >= len(m.filteredArticles) { target = len(m.filteredArticles) - 1 }
-m.cursor = target
-}
-// Adjust scroll
-if m.cursor < m.scrollOffset || m.cursor >= m.scrollOffset+(m.height-4) {
-m.scrollOffset = m.cursor - (m.height-4)/2
-if m.scrollOffset < 0 { m.scrollOffset = 0 }
-}
-m.inputBuffer = ""
-
-case "enter":
-			if len(m.filteredArticles) > 0 {
-				article := m.filteredArticles[m.cursor]
-				m.inputBuffer = ""
-				return m, func() tea.Msg {
-					added, err := m.svc.ToggleReadingInCurrentWeek(context.Background(), article.ID)
-					if err != nil {
-						return StatusMsg(fmt.Sprintf("Error: %v", err))
-					}
-					if added {
-						return StatusMsg("Added to reading list")
+				// Actually 'gg' is go to top. 'G' is go to bottom.
+				// '10G' is go to line 10.
+				// '10gg' is go to line 10.
+
+				// Since we don't have a command buffer for 'gg', let's just implement 'G' for now.
+				// Or we can treat 'g' as 'home' if no buffer?
+				m.cursor = 0
+				m.scrollOffset = 0
+			} else {
+				// If number provided, go to that line (1-based)
+				target := count - 1
+				if target < 0 {
+					target = 0
+				}
+				if target >= len(m.filteredArticles) {
+					target = len(m.filteredArticles) - 1
+				}
+				m.cursor = target
+				// Adjust scroll
+				if m.cursor < m.scrollOffset || m.cursor >= m.scrollOffset+(m.height-4) {
+					m.scrollOffset = m.cursor - (m.height-4)/2
+					if m.scrollOffset < 0 {
+						m.scrollOffset = 0
 					}
-					return StatusMsg("Removed from reading list")
 				}
 			}
 			m.inputBuffer = ""
+		case "G":
+			if m.inputBuffer == "" {
+				// Go to bottom
+				m.cursor = len(m.filteredArticles) - 1
+			} else {
+				// Go to line N
+				target := count - 1
+				if target < 0 {
+					target = 0
+				}
+				if target >= len(m.filteredArticles) {
+					target = len(m.filteredArticles) - 1
+				}
+				m.cursor = target
+			}
+			// Adjust scroll
+			if m.cursor < m.scrollOffset || m.cursor >= m.scrollOffset+(m.height-4) {
+				m.scrollOffset = m.cursor - (m.height-4)/2
+				if m.scrollOffset < 0 {
+					m.scrollOffset = 0
+				}
+			}
+			m.inputBuffer = ""
+
+		case "enter":
+			m.inputBuffer = ""
+			if len(m.filteredArticles) > 0 {
+				return m, m.toggleWeek(m.filteredArticles[m.cursor])
+			}
+		case "i", "l", "right":
+			m.inputBuffer = ""
+			if len(m.filteredArticles) > 0 {
+				m.view = ViewDetail
+				if m.weekIDs == nil {
+					return m, m.loadWeek()
+				}
+			}
+		case "d":
+			m.inputBuffer = ""
+			if len(m.filteredArticles) > 0 {
+				return m, m.markDone(m.filteredArticles[m.cursor])
+			}
+		case "w":
+			m.inputBuffer = ""
+			m.view = ViewWeek
+			if m.weekIDs == nil && m.weekErr == nil {
+				return m, m.loadWeek()
+			}
+		case "esc":
+			if m.searchQuery != "" {
+				m.searchQuery = ""
+				m.applyFilter()
+				m.cursor = 0
+				m.scrollOffset = 0
+			}
+			m.inputBuffer = ""
+		case "?", "ctrl+f":
+			// Enter search mode
+			m.view = ViewSearch
+			m.backupSearchQuery = m.searchQuery
+			m.searchInput = newSearchInput(m.searchQuery)
+			m.inputBuffer = ""
+			return m, textinput.Blink
 		case "/":
-// Enter filter mode
-m.view = ViewFilter
-m.backupSelectedTags = make(map[string]bool)
-for k, v := range m.selectedTags {
-m.backupSelectedTags[k] = v
+			// Enter filter mode
+			m.view = ViewFilter
+			m.backupSelectedTags = copyTags(m.selectedTags)
+			m.backupExcludedTags = copyTags(m.excludedTags)
+			m.backupMatchAllTags = m.matchAllTags
+			m.cursor = 0
+			m.scrollOffset = 0
+			m.inputBuffer = ""
+		default:
+			// Clear buffer on unknown key
+			m.inputBuffer = ""
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "h", "left":
+			m.view = ViewList
+		case "enter", "o":
+			if m.cursor < len(m.filteredArticles) {
+				return m, m.openArticle(m.filteredArticles[m.cursor])
+			}
+		case "w":
+			if m.cursor < len(m.filteredArticles) {
+				return m, m.toggleWeek(m.filteredArticles[m.cursor])
+			}
+		case "y":
+			if m.cursor < len(m.filteredArticles) {
+				return m, copyURL(m.filteredArticles[m.cursor].URL)
+			}
+		case "d":
+			if m.cursor < len(m.filteredArticles) {
+				return m, m.markDone(m.filteredArticles[m.cursor])
+			}
+		}
+	}
+	return m, nil
 }
-m.cursor = 0
-m.scrollOffset = 0
-m.inputBuffer = ""
-default:
-// Clear buffer on unknown key
-m.inputBuffer = ""
+
+func (m Model) updateWeek(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.proposal != nil {
+		switch key.String() {
+		case "enter", "y":
+			plan := *m.proposal
+			m.proposal = nil
+			return m, m.applyPlan(plan)
+		case "g":
+			return m, m.proposePlan()
+		case "esc", "n":
+			m.proposal = nil
+		}
+		return m, nil
+	}
+
+	switch key.String() {
+	case "g":
+		if m.weekIDs != nil {
+			return m, m.proposePlan()
+		}
+	case "esc", "q", "w", "h", "left":
+		m.view = ViewList
+	case "up", "k":
+		if m.weekCursor > 0 {
+			m.weekCursor--
+		}
+	case "down", "j":
+		if m.weekCursor < len(m.weekList)-1 {
+			m.weekCursor++
+		}
+	case "K", "shift+up":
+		return m, m.moveInWeek(m.weekCursor - 1)
+	case "J", "shift+down":
+		return m, m.moveInWeek(m.weekCursor + 1)
+	case "x", "delete":
+		if m.weekCursor < len(m.weekList) {
+			return m, m.toggleWeek(readings.Article{ID: m.weekList[m.weekCursor]})
+		}
+	case "enter", "o":
+		if a, ok := m.weekArticle(m.weekCursor); ok {
+			return m, m.openArticle(a)
+		}
+	case "[":
+		return m, m.pickWeek(m.weekBefore())
+	case "]":
+		return m, m.pickWeek(m.weekAfter())
+	case "t":
+		if !m.weekAt.IsZero() {
+			return m, m.pickWeek(time.Time{})
+		}
+	}
+	return m, nil
 }
+
+func (m Model) proposePlan() tea.Cmd {
+	svc, opts, at, t := m.svc, m.planOpts, m.weekAt, m.weekTime()
+	return func() tea.Msg {
+		plan, err := svc.ProposePlan(context.Background(), t, opts)
+		return PlanProposedMsg{At: at, Plan: plan, Err: err}
+	}
 }
-return m, nil
+
+func (m Model) applyPlan(plan readings.Plan) tea.Cmd {
+	svc, at, t := m.svc, m.weekAt, m.weekTime()
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := svc.ApplyPlan(ctx, plan); err != nil {
+			return StatusMsg(fmt.Sprintf("Error: %v", err))
+		}
+		week, err := svc.WeekAt(ctx, t)
+		if err != nil {
+			return StatusMsg(fmt.Sprintf("Error: %v", err))
+		}
+		return PlanAppliedMsg{At: at, Added: len(plan.Proposed), ReadingList: week.ReadingListIDs, Queued: m.queued(ctx)}
+	}
 }
 
-func (m Model) updateDetail(msg tea.Msg) (tea.Model, tea.Cmd) {
-switch msg := msg.(type) {
-case tea.KeyMsg:
-switch msg.String() {
-case "esc", "q":
-m.view = ViewList
-case "enter":
-if m.cursor < len(m.filteredArticles) {
-article := m.filteredArticles[m.cursor]
-return m, openUrl(article.URL)
+// weekBefore returns a time in the week before the one shown. Weeks are
+// stepped by their spans, or by seven days when none was found.
+func (m Model) weekBefore() time.Time {
+	if m.weekErr == nil && !m.weekStart.IsZero() {
+		return m.weekStart.Add(-time.Second)
+	}
+	return m.weekTime().AddDate(0, 0, -7)
+}
+
+// weekAfter returns a time in the week after the one shown.
+func (m Model) weekAfter() time.Time {
+	if m.weekErr == nil && !m.weekEnd.IsZero() {
+		return m.weekEnd.Add(time.Second)
+	}
+	return m.weekTime().AddDate(0, 0, 7)
 }
+
+// weekTime returns the time the planned week is looked up by.
+func (m Model) weekTime() time.Time {
+	if m.weekAt.IsZero() {
+		return time.Now()
+	}
+	return m.weekAt
 }
+
+// pickWeek plans the week containing at instead, or the current week if at
+// is zero, and loads it.
+func (m *Model) pickWeek(at time.Time) tea.Cmd {
+	m.weekAt = at
+	m.proposal = nil
+	m.weekIDs, m.weekList, m.weekErr = nil, nil, nil
+	m.weekStart, m.weekEnd = time.Time{}, time.Time{}
+	m.weekCursor = 0
+	return m.loadWeek()
+}
+
+// moveInWeek moves the selected reading list item to position. The list is
+// reordered right away and saved in the background, one reorder at a time so
+// the moves reach the service in the order they were made; the service
+// confirms the order it saved.
+func (m *Model) moveInWeek(position int) tea.Cmd {
+	if m.weekCursor >= len(m.weekList) || position < 0 || position >= len(m.weekList) {
+		return nil
+	}
+	id := m.weekList[m.weekCursor]
+	m.weekList = slices.Insert(slices.Delete(slices.Clone(m.weekList), m.weekCursor, m.weekCursor+1), position, id)
+	m.weekCursor = position
+
+	if m.moving {
+		m.movePending = true
+		return nil
+	}
+	return m.reorderWeek()
+}
+
+// reorderWeek saves the order of the reading list shown.
+func (m *Model) reorderWeek() tea.Cmd {
+	m.moving = true
+	svc, at, t, order := m.svc, m.weekAt, m.weekTime(), slices.Clone(m.weekList)
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := svc.ReorderWeekAt(ctx, t, order); err != nil {
+			return WeekMovedMsg{At: at, Err: err}
+		}
+		week, err := svc.WeekAt(ctx, t)
+		if err != nil {
+			return WeekMovedMsg{At: at, Err: err}
+		}
+		return WeekMovedMsg{At: at, ReadingList: week.ReadingListIDs, Queued: m.queued(ctx)}
+	}
 }
-return m, nil
+
+// weekArticle returns the cached article at index i of the reading list.
+// Articles that are done or not synced yet are not in the cache.
+func (m Model) weekArticle(i int) (readings.Article, bool) {
+	if i < 0 || i >= len(m.weekList) {
+		return readings.Article{}, false
+	}
+	for _, a := range m.articles {
+		if a.ID == m.weekList[i] {
+			return a, true
+		}
+	}
+	return readings.Article{}, false
 }
 
 func (m Model) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
-switch msg := msg.(type) {
-case tea.KeyMsg:
-switch msg.String() {
-case "up", "k":
-if m.cursor > 0 {
-m.cursor--
-if m.cursor < m.scrollOffset {
-m.scrollOffset = m.cursor
-}
-}
-case "down", "j":
-if m.cursor < len(m.tags)-1 {
-m.cursor++
-if m.cursor >= m.scrollOffset+(m.height-4) {
-m.scrollOffset++
-}
-}
-case " ":
-if m.cursor < len(m.tags) {
-tag := m.tags[m.cursor]
-if m.selectedTags[tag] {
-delete(m.selectedTags, tag)
-} else {
-m.selectedTags[tag] = true
-}
-}
-case "right":
-// Select all
-for _, t := range m.tags {
-m.selectedTags[t] = true
-}
-case "enter":
-// Apply filter
-m.applyFilter()
-m.view = ViewList
-m.cursor = 0
-m.scrollOffset = 0
-case "esc":
-// Cancel
-m.selectedTags = m.backupSelectedTags
-m.view = ViewList
-m.cursor = 0
-m.scrollOffset = 0
-}
-}
-return m, nil
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+				if m.cursor < m.scrollOffset {
+					m.scrollOffset = m.cursor
+				}
+			}
+		case "down", "j":
+			if m.cursor < len(m.tags)-1 {
+				m.cursor++
+				if m.cursor >= m.scrollOffset+(m.height-4) {
+					m.scrollOffset++
+				}
+			}
+		case " ":
+			if m.cursor < len(m.tags) {
+				tag := m.tags[m.cursor]
+				if m.selectedTags[tag] {
+					delete(m.selectedTags, tag)
+				} else {
+					m.selectedTags[tag] = true
+					delete(m.excludedTags, tag)
+				}
+			}
+		case "x":
+			if m.cursor < len(m.tags) {
+				tag := m.tags[m.cursor]
+				if m.excludedTags == nil {
+					m.excludedTags = make(map[string]bool)
+				}
+				if m.excludedTags[tag] {
+					delete(m.excludedTags, tag)
+				} else {
+					m.excludedTags[tag] = true
+					delete(m.selectedTags, tag)
+				}
+			}
+		case "a":
+			m.matchAllTags = !m.matchAllTags
+		case "right":
+			// Select all
+			for _, t := range m.tags {
+				m.selectedTags[t] = true
+			}
+			m.excludedTags = make(map[string]bool)
+		case "enter":
+			// Apply filter
+			m.applyFilter()
+			m.view = ViewList
+			m.cursor = 0
+			m.scrollOffset = 0
+		case "esc":
+			// Cancel
+			m.selectedTags = m.backupSelectedTags
+			m.excludedTags = m.backupExcludedTags
+			m.matchAllTags = m.backupMatchAllTags
+			m.view = ViewList
+			m.cursor = 0
+			m.scrollOffset = 0
+		}
+	}
+	return m, nil
 }
 
+func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+				if m.cursor < m.scrollOffset {
+					m.scrollOffset = m.cursor
+				}
+			}
+			return m, nil
+		case "down", "ctrl+n":
+			if m.cursor < len(m.filteredArticles)-1 {
+				m.cursor++
+				if m.cursor >= m.scrollOffset+(m.height-3) {
+					m.scrollOffset++
+				}
+			}
+			return m, nil
+		case "enter":
+			// Keep the results
+			m.view = ViewList
+			m.searchInput.Blur()
+			return m, nil
+		case "esc":
+			// Cancel
+			m.view = ViewList
+			m.searchInput.Blur()
+			m.searchQuery = m.backupSearchQuery
+			m.applyFilter()
+			m.cursor = 0
+			m.scrollOffset = 0
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if query := strings.TrimSpace(m.searchInput.Value()); query != m.searchQuery {
+		m.searchQuery = query
+		m.applyFilter()
+		m.cursor = 0
+		m.scrollOffset = 0
+	}
+	return m, cmd
+}
+
+func newSearchInput(value string) textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "? "
+	ti.Placeholder = "Search titles, URLs and tags"
+	ti.SetValue(value)
+	ti.Focus()
+	return ti
+}
+
+// applyFilter narrows the articles to the selected tags and, while
+// searching, to fuzzy matches of the query ranked best first.
 func (m *Model) applyFilter() {
-if len(m.selectedTags) == 0 {
-m.filteredArticles = m.articles
-return
+	filter := m.tagFilter()
+	terms := strings.Fields(m.searchQuery)
+	m.searchMatches = nil
+	if filter.IsEmpty() && len(terms) == 0 {
+		m.filteredArticles = m.articles
+		return
+	}
+
+	var filtered []readings.Article
+	var scores []int
+	if len(terms) > 0 {
+		m.searchMatches = make(map[string][]int)
+	}
+	for _, a := range m.articles {
+		if !filter.Matches(a) {
+			continue
+		}
+		if len(terms) > 0 {
+			score, positions, ok := matchArticle(a, terms)
+			if !ok {
+				continue
+			}
+			scores = append(scores, score)
+			m.searchMatches[a.ID] = positions
+		}
+		filtered = append(filtered, a)
+	}
+
+	if len(terms) > 0 {
+		sort.Stable(byScore{filtered, scores})
+	}
+	m.filteredArticles = filtered
+}
+
+// byScore sorts articles by descending score, keeping ties in list order.
+type byScore struct {
+	articles []readings.Article
+	scores   []int
+}
+
+func (s byScore) Len() int           { return len(s.articles) }
+func (s byScore) Less(i, j int) bool { return s.scores[i] > s.scores[j] }
+func (s byScore) Swap(i, j int) {
+	s.articles[i], s.articles[j] = s.articles[j], s.articles[i]
+	s.scores[i], s.scores[j] = s.scores[j], s.scores[i]
+}
+
+// tagFilter builds the filter for the tags selected in the filter view.
+func (m Model) tagFilter() readings.TagFilter {
+	filter := readings.TagFilter{MatchAll: m.matchAllTags}
+	for t, on := range m.selectedTags {
+		if on {
+			filter.Include = append(filter.Include, t)
+		}
+	}
+	for t, on := range m.excludedTags {
+		if on {
+			filter.Exclude = append(filter.Exclude, t)
+		}
+	}
+	return filter
+}
+
+func copyTags(tags map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(tags))
+	for k, v := range tags {
+		result[k] = v
+	}
+	return result
+}
+
+func (m Model) loadWeek() tea.Cmd {
+	at, t := m.weekAt, m.weekTime()
+	return func() tea.Msg {
+		week, err := m.svc.WeekAt(context.Background(), t)
+		return WeekLoadedMsg{At: at, ReadingList: week.ReadingListIDs, Start: week.Start, End: week.End, Err: err}
+	}
+}
+
+func (m Model) toggleWeek(article readings.Article) tea.Cmd {
+	at, t := m.weekAt, m.weekTime()
+	return func() tea.Msg {
+		ctx := context.Background()
+		added, err := m.svc.ToggleReadingInWeekAt(ctx, t, article.ID)
+		if err != nil {
+			return StatusMsg(fmt.Sprintf("Error: %v", err))
+		}
+		week, err := m.svc.WeekAt(ctx, t)
+		if err != nil {
+			return StatusMsg(fmt.Sprintf("Error: %v", err))
+		}
+		return WeekToggledMsg{At: at, Added: added, ReadingList: week.ReadingListIDs, Queued: m.queued(ctx)}
+	}
 }
 
-var filtered []readings.Article
-for _, a := range m.articles {
-match := false
-for _, t := range a.Tags {
-if m.selectedTags[t] {
-match = true
-break
+func (m *Model) setWeek(readingList []string) {
+	m.weekIDs = make(map[string]bool, len(readingList))
+	for _, id := range readingList {
+		m.weekIDs[id] = true
+	}
+	m.weekList = readingList
+	m.weekErr = nil
+	if m.weekCursor >= len(readingList) {
+		m.weekCursor = max(0, len(readingList)-1)
+	}
 }
+
+func (m Model) markDone(article readings.Article) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := m.svc.MarkDone(ctx, article.ID); err != nil {
+			return StatusMsg(fmt.Sprintf("Error: %v", err))
+		}
+		return ArticleDoneMsg{ID: article.ID, Queued: m.queued(ctx)}
+	}
 }
-if match {
-filtered = append(filtered, a)
+
+// queued counts the changes that have not reached Notion yet.
+func (m Model) queued(ctx context.Context) int {
+	ops, err := m.svc.Outbox(ctx)
+	if err != nil {
+		return 0
+	}
+	return len(ops)
 }
+
+func queuedNote(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return " (1 change not yet in Notion)"
+	default:
+		return fmt.Sprintf(" (%d changes not yet in Notion)", n)
+	}
 }
-m.filteredArticles = filtered
+
+func (m Model) startSync() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		_, err := m.syncFn(ctx)
+
+		// Read the cache even if the sync failed; it may have been updated
+		// by another process.
+		articles, loadErr := m.svc.GetAll(ctx)
+		if err == nil && loadErr != nil {
+			err = fmt.Errorf("failed to reload articles: %w", loadErr)
+		}
+		return SyncedMsg{Err: err, Reloaded: loadErr == nil, Articles: articles}
+	}
 }
 
-func openUrl(url string) tea.Cmd {
-return func() tea.Msg {
-var cmd string
-var args []string
+// mergeArticles replaces the articles with fresh, keeping the list order
+// and adding new articles at the end, and returns how many were added and
+// removed. The selected article, tag selection and search stay as they are.
+func (m *Model) mergeArticles(fresh []readings.Article) (added, removed int) {
+	selected := ""
+	if m.view != ViewFilter && m.cursor < len(m.filteredArticles) {
+		selected = m.filteredArticles[m.cursor].ID
+	}
+	selectedTag := ""
+	if m.view == ViewFilter && m.cursor < len(m.tags) {
+		selectedTag = m.tags[m.cursor]
+	}
+
+	byID := make(map[string]readings.Article, len(fresh))
+	for _, a := range fresh {
+		byID[a.ID] = a
+	}
 
-switch runtime.GOOS {
-case "windows":
-cmd = "cmd"
-args = []string{"/c", "start"}
-case "darwin":
-cmd = "open"
-default: // "linux", "freebsd", "openbsd", "netbsd"
-cmd = "xdg-open"
+	merged := make([]readings.Article, 0, len(fresh))
+	kept := make(map[string]bool, len(m.articles))
+	for _, a := range m.articles {
+		if updated, ok := byID[a.ID]; ok {
+			merged = append(merged, updated)
+			kept[a.ID] = true
+		} else {
+			removed++
+		}
+	}
+	for _, a := range fresh {
+		if !kept[a.ID] {
+			merged = append(merged, a)
+			added++
+		}
+	}
+
+	m.articles = merged
+	m.tags = uniqueTags(merged)
+	m.applyFilter()
+
+	switch {
+	case m.view == ViewFilter:
+		m.cursor = indexOf(m.tags, selectedTag, m.cursor)
+		m.clampCursor(len(m.tags))
+	case selected != "":
+		found := false
+		for i, a := range m.filteredArticles {
+			if a.ID == selected {
+				m.cursor, found = i, true
+				break
+			}
+		}
+		if !found && m.view == ViewDetail {
+			m.view = ViewList
+		}
+		m.clampCursor(len(m.filteredArticles))
+	default:
+		m.clampCursor(len(m.filteredArticles))
+	}
+	return added, removed
 }
-args = append(args, url)
-c := exec.Command(cmd, args...)
-return c.Start()
+
+// indexOf returns the index of s in list, or fallback if it is missing.
+func indexOf(list []string, s string, fallback int) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return fallback
+}
+
+// clampCursor keeps the cursor within a list of n items and on screen.
+func (m *Model) clampCursor(n int) {
+	if m.cursor >= n {
+		m.cursor = n - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.scrollOffset > m.cursor {
+		m.scrollOffset = m.cursor
+	}
+	if visible := m.height - 4; visible > 0 && m.cursor >= m.scrollOffset+visible {
+		m.scrollOffset = m.cursor - visible + 1
+	}
+}
+
+func syncStatus(added, removed int) string {
+	switch {
+	case added == 0 && removed == 0:
+		return "Up to date"
+	case removed == 0:
+		return fmt.Sprintf("Synced: %d new", added)
+	case added == 0:
+		return fmt.Sprintf("Synced: %d gone", removed)
+	default:
+		return fmt.Sprintf("Synced: %d new, %d gone", added, removed)
+	}
+}
+
+// removeArticle drops an article from both lists, keeping the cursor in range.
+func (m *Model) removeArticle(id string) {
+	m.articles = withoutArticle(m.articles, id)
+	m.filteredArticles = withoutArticle(m.filteredArticles, id)
+	m.clampCursor(len(m.filteredArticles))
+}
+
+func withoutArticle(articles []readings.Article, id string) []readings.Article {
+	result := make([]readings.Article, 0, len(articles))
+	for _, a := range articles {
+		if a.ID != id {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func copyURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(url); err != nil {
+			return StatusMsg(fmt.Sprintf("Error: failed to copy URL: %v", err))
+		}
+		return StatusMsg("Copied URL")
+	}
 }
+
+// openArticle opens the article in the browser and records it in the
+// history.
+func (m Model) openArticle(article readings.Article) tea.Cmd {
+	return func() tea.Msg {
+		if err := openUrl(article.URL); err != nil {
+			return StatusMsg(fmt.Sprintf("Error: failed to open URL: %v", err))
+		}
+		if err := m.svc.RecordOpened(context.Background(), article.ID); err != nil {
+			return StatusMsg(fmt.Sprintf("Error: %v", err))
+		}
+		return nil
+	}
+}
+
+func openUrl(url string) error {
+	var cmd string
+	var args []string
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = "cmd"
+		args = []string{"/c", "start"}
+	case "darwin":
+		cmd = "open"
+	default: // "linux", "freebsd", "openbsd", "netbsd"
+		cmd = "xdg-open"
+	}
+	args = append(args, url)
+	return exec.Command(cmd, args...).Start()
 }
@@ -1,11 +1,14 @@
 package tui
 
 import (
+"fmt"
 "testing"
+"time"
 
 tea "github.com/charmbracelet/bubbletea"
 "github.com/stretchr/testify/assert"
 "productivity.go/internal/readings"
+"productivity.go/internal/sync"
 )
 
 func TestUpdate_Quit(t *testing.T) {
@@ -106,3 +109,448 @@ func TestUpdate_Filter(t *testing.T) {
 	assert.False(t, model.selectedTags["rust"]) // Should be reverted
 	assert.True(t, model.selectedTags["go"])    // Should be kept
 }
+
+func TestUpdate_ArticleDone(t *testing.T) {
+	articles := []readings.Article{
+		{ID: "1", Title: "First"},
+		{ID: "2", Title: "Second"},
+	}
+	m := Model{
+		articles:         articles,
+		filteredArticles: articles,
+		selectedTags:     make(map[string]bool),
+		view:             ViewDetail,
+		cursor:           1,
+	}
+
+	newM, cmd := m.Update(ArticleDoneMsg{ID: "2"})
+	model := newM.(Model)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, ViewList, model.view)
+	assert.Equal(t, 0, model.cursor)
+	assert.Len(t, model.articles, 1)
+	assert.Len(t, model.filteredArticles, 1)
+	assert.Equal(t, "First", model.filteredArticles[0].Title)
+
+	_, cmd = model.Update(ArticleDoneMsg{ID: "1", Queued: 2})
+	assert.Equal(t, StatusMsg("Marked as done (2 changes not yet in Notion)"), cmd())
+}
+
+func TestUpdate_FilterExclude(t *testing.T) {
+	articles := []readings.Article{
+		{Title: "Go Article", Tags: []string{"go"}},
+		{Title: "Rust Article", Tags: []string{"rust"}},
+		{Title: "Both Article", Tags: []string{"go", "rust"}},
+	}
+	m := Model{
+		articles:         articles,
+		filteredArticles: articles,
+		tags:             []string{"go", "rust"},
+		selectedTags:     map[string]bool{"go": true},
+		view:             ViewFilter,
+		cursor:           1, // rust
+	}
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model := newM.(Model)
+	assert.True(t, model.excludedTags["rust"])
+
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newM.(Model)
+	assert.Equal(t, 1, len(model.filteredArticles))
+	assert.Equal(t, "Go Article", model.filteredArticles[0].Title)
+
+	// Matching all selected tags.
+	model.view = ViewFilter
+	model.selectedTags = map[string]bool{"go": true, "rust": true}
+	model.excludedTags = map[string]bool{}
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	model = newM.(Model)
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newM.(Model)
+	assert.Equal(t, 1, len(model.filteredArticles))
+	assert.Equal(t, "Both Article", model.filteredArticles[0].Title)
+
+	// Including an excluded tag clears the exclusion.
+	model.excludedTags = map[string]bool{"rust": true}
+	model.selectedTags = map[string]bool{}
+	model.view = ViewFilter
+	model.cursor = 1
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" ")})
+	model = newM.(Model)
+	assert.True(t, model.selectedTags["rust"])
+	assert.False(t, model.excludedTags["rust"])
+}
+
+func TestUpdate_Search(t *testing.T) {
+	articles := []readings.Article{
+		{ID: "1", Title: "Go Article", URL: "https://go.dev/a", Tags: []string{"go"}},
+		{ID: "2", Title: "Rust Article", URL: "https://www.rust-lang.org/b", Tags: []string{"rust"}},
+		{ID: "3", Title: "Go and Rust", URL: "https://example.com/c", Tags: []string{"go", "rust"}},
+	}
+	m := Model{
+		articles:         articles,
+		filteredArticles: articles,
+		tags:             []string{"go", "rust"},
+		selectedTags:     map[string]bool{},
+		view:             ViewList,
+	}
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	model := newM.(Model)
+	assert.Equal(t, ViewSearch, model.view)
+
+	// "q" is typed into the search box instead of quitting.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	model = newM.(Model)
+	assert.Equal(t, "q", model.searchInput.Value())
+	assert.Empty(t, model.filteredArticles)
+
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	model = newM.(Model)
+	assert.Len(t, model.filteredArticles, 3)
+
+	// Results narrow as you type, title matches first.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("rust")})
+	model = newM.(Model)
+	assert.Equal(t, []string{"2", "3"}, ids(model.filteredArticles))
+	assert.Equal(t, []int{0, 1, 2, 3}, model.searchMatches["2"])
+
+	// Hosts match too.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" lang")})
+	model = newM.(Model)
+	assert.Equal(t, []string{"2"}, ids(model.filteredArticles))
+
+	// Keep the results and combine them with a tag filter.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newM.(Model)
+	assert.Equal(t, ViewList, model.view)
+	assert.Equal(t, "rust lang", model.searchQuery)
+
+	model.searchQuery = "rust"
+	model.selectedTags = map[string]bool{"go": true}
+	model.view = ViewFilter
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newM.(Model)
+	assert.Equal(t, []string{"3"}, ids(model.filteredArticles))
+
+	// Cancelling a new search restores the previous one.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	model = newM.(Model)
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	model = newM.(Model)
+	assert.Empty(t, model.filteredArticles)
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newM.(Model)
+	assert.Equal(t, "rust", model.searchQuery)
+	assert.Equal(t, []string{"3"}, ids(model.filteredArticles))
+
+	// Esc in the list clears the search but keeps the tag filter.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newM.(Model)
+	assert.Equal(t, "", model.searchQuery)
+	assert.Equal(t, []string{"1", "3"}, ids(model.filteredArticles))
+}
+
+func ids(articles []readings.Article) []string {
+	result := make([]string, len(articles))
+	for i, a := range articles {
+		result[i] = a.ID
+	}
+	return result
+}
+
+func TestUpdate_Detail(t *testing.T) {
+	articles := []readings.Article{
+		{ID: "1", Title: "Go Article", URL: "https://www.go.dev/a", Tags: []string{"go"}, ReadingMinutes: 7, Notes: "Read the part on generics"},
+		{ID: "2", Title: "Rust Article", URL: "https://rust-lang.org/b"},
+	}
+	m := Model{
+		articles:         articles,
+		filteredArticles: articles,
+		view:             ViewList,
+		width:            80,
+		height:           20,
+	}
+
+	// Enter toggles the week instead of opening the details.
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model := newM.(Model)
+	assert.Equal(t, ViewList, model.view)
+	assert.NotNil(t, cmd)
+
+	// The week is loaded the first time the details open.
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	model = newM.(Model)
+	assert.Equal(t, ViewDetail, model.view)
+	assert.NotNil(t, cmd)
+	assert.Contains(t, model.View(), "loading…")
+
+	newM, _ = model.Update(WeekLoadedMsg{ReadingList: []string{"1"}})
+	model = newM.(Model)
+	view := model.View()
+	assert.Contains(t, view, "yes, on the reading list")
+	assert.Contains(t, view, "go.dev")
+	assert.Contains(t, view, "~7 min")
+	assert.Contains(t, view, "Read the part on generics")
+
+	newM, _ = model.Update(WeekToggledMsg{Added: false, ReadingList: nil})
+	model = newM.(Model)
+	assert.False(t, model.weekIDs["1"])
+	assert.NotNil(t, model.weekIDs)
+
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	model = newM.(Model)
+	assert.Equal(t, ViewList, model.view)
+
+	// Once loaded, the week is not fetched again.
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	model = newM.(Model)
+	assert.Equal(t, ViewDetail, model.view)
+	assert.Nil(t, cmd)
+
+	// q returns to the list instead of quitting.
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	model = newM.(Model)
+	assert.Equal(t, ViewList, model.view)
+	assert.Nil(t, cmd)
+}
+
+func TestUpdate_WeekLoadError(t *testing.T) {
+	m := Model{view: ViewDetail, filteredArticles: []readings.Article{{ID: "1"}}}
+
+	newM, cmd := m.Update(WeekLoadedMsg{Err: assert.AnError})
+	model := newM.(Model)
+	assert.NotNil(t, cmd)
+	assert.Contains(t, model.View(), "unknown (no current week)")
+}
+
+func TestUpdate_WeekNavigation(t *testing.T) {
+	articles := []readings.Article{{ID: "1", Title: "First"}}
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 7).Add(-time.Second)
+	m := Model{articles: articles, filteredArticles: articles, view: ViewWeek, width: 80, height: 20}
+	newM, _ := m.Update(WeekLoadedMsg{ReadingList: []string{"1"}, Start: start, End: end})
+	model := newM.(Model)
+
+	// The next week starts right after this one ends.
+	newM, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = newM.(Model)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, end.Add(time.Second), model.weekAt)
+	assert.Nil(t, model.weekIDs)
+
+	// A late answer for the previous week is dropped.
+	newM, _ = model.Update(WeekLoadedMsg{ReadingList: []string{"1"}, Start: start, End: end})
+	model = newM.(Model)
+	assert.Nil(t, model.weekIDs)
+
+	newM, _ = model.Update(WeekLoadedMsg{At: model.weekAt, ReadingList: nil, Start: end.Add(time.Second), End: end.AddDate(0, 0, 7)})
+	model = newM.(Model)
+	view := model.View()
+	assert.Contains(t, view, "Next week · "+end.Add(time.Second).Format("Jan 2"))
+	assert.Contains(t, view, "0 planned next week")
+
+	// Without a week in Notion, weeks are stepped by seven days.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = newM.(Model)
+	at := model.weekAt
+	newM, _ = model.Update(WeekLoadedMsg{At: at, Err: assert.AnError})
+	model = newM.(Model)
+	assert.Contains(t, model.View(), "No week to plan")
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	model = newM.(Model)
+	assert.Equal(t, at.AddDate(0, 0, 7), model.weekAt)
+
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	model = newM.(Model)
+	assert.NotNil(t, cmd)
+	assert.True(t, model.weekAt.IsZero())
+
+	// q returns to the list instead of quitting.
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	model = newM.(Model)
+	assert.Equal(t, ViewList, model.view)
+	assert.Nil(t, cmd)
+}
+
+func TestUpdate_WeekPlanProposal(t *testing.T) {
+	articles := []readings.Article{{ID: "1", Title: "First"}, {ID: "2", Title: "Second", Tags: []string{"go"}}}
+	m := Model{articles: articles, filteredArticles: articles, view: ViewWeek, width: 80, height: 20}
+	m.setWeek([]string{"1"})
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	model := newM.(Model)
+	assert.NotNil(t, cmd)
+
+	plan := readings.Plan{Week: readings.Week{ReadingListIDs: []string{"1"}}, Planned: articles[:1], Proposed: articles[1:], Minutes: 20}
+	newM, _ = model.Update(PlanProposedMsg{Plan: plan})
+	model = newM.(Model)
+	view := model.View()
+	assert.Contains(t, view, "Proposed: 1 more, ~20 min in total")
+	assert.Contains(t, view, "+ 2. Second · go")
+
+	// Other keys wait for an answer; esc discards the proposal.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model = newM.(Model)
+	assert.NotNil(t, model.proposal)
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newM.(Model)
+	assert.Nil(t, model.proposal)
+	assert.Equal(t, ViewWeek, model.view)
+
+	newM, _ = model.Update(PlanProposedMsg{Plan: plan})
+	model = newM.(Model)
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = newM.(Model)
+	assert.NotNil(t, cmd)
+	assert.Nil(t, model.proposal)
+
+	newM, cmd = model.Update(PlanAppliedMsg{Added: 1, ReadingList: []string{"1", "2"}})
+	model = newM.(Model)
+	assert.Equal(t, []string{"1", "2"}, model.weekList)
+	assert.Equal(t, StatusMsg("Added 1 article(s) to the reading list"), cmd())
+}
+
+func TestUpdate_SyncedMergesArticles(t *testing.T) {
+	articles := []readings.Article{
+		{ID: "1", Title: "Go One", Tags: []string{"go"}},
+		{ID: "2", Title: "Rust", Tags: []string{"rust"}},
+		{ID: "3", Title: "Go Two", Tags: []string{"go"}},
+	}
+	m := Model{
+		articles:     articles,
+		tags:         []string{"go", "rust"},
+		selectedTags: map[string]bool{"go": true},
+		view:         ViewList,
+		syncing:      true,
+	}
+	m.applyFilter()
+	m.cursor = 1 // Go Two
+
+	fresh := []readings.Article{
+		{ID: "4", Title: "Go Three", Tags: []string{"go", "new"}},
+		{ID: "3", Title: "Go Two, edited", Tags: []string{"go"}},
+		{ID: "2", Title: "Rust", Tags: []string{"rust"}},
+	}
+	newM, cmd := m.Update(SyncedMsg{Reloaded: true, Articles: fresh})
+	model := newM.(Model)
+
+	assert.False(t, model.syncing)
+	assert.Equal(t, StatusMsg("Synced: 1 new, 1 gone"), cmd())
+	assert.Equal(t, []string{"go", "new", "rust"}, model.tags)
+	assert.Equal(t, map[string]bool{"go": true}, model.selectedTags)
+
+	// Kept articles keep their place, new ones are appended.
+	var titles []string
+	for _, a := range model.filteredArticles {
+		titles = append(titles, a.Title)
+	}
+	assert.Equal(t, []string{"Go Two, edited", "Go Three"}, titles)
+	assert.Equal(t, 0, model.cursor, "The selected article stays selected")
+	assert.Len(t, model.articles, 3)
+}
+
+func TestUpdate_SyncedRemovesOpenArticle(t *testing.T) {
+	articles := []readings.Article{{ID: "1", Title: "First"}, {ID: "2", Title: "Second"}}
+	m := Model{
+		articles:         articles,
+		filteredArticles: articles,
+		view:             ViewDetail,
+		cursor:           1,
+		syncing:          true,
+	}
+
+	newM, _ := m.Update(SyncedMsg{Reloaded: true, Articles: articles[:1]})
+	model := newM.(Model)
+	assert.Equal(t, ViewList, model.view)
+	assert.Equal(t, 0, model.cursor)
+}
+
+func TestUpdate_SyncFailed(t *testing.T) {
+	articles := []readings.Article{{ID: "1", Title: "First"}}
+	m := Model{articles: articles, filteredArticles: articles, syncing: true}
+	assert.Contains(t, m.View(), "syncing…")
+
+	newM, cmd := m.Update(SyncedMsg{Err: assert.AnError})
+	model := newM.(Model)
+	assert.False(t, model.syncing)
+	assert.Equal(t, articles, model.articles)
+	assert.Equal(t, StatusMsg("Sync failed: "+assert.AnError.Error()), cmd())
+	assert.NotContains(t, model.View(), "syncing…")
+
+	// A sync running elsewhere is not a failure.
+	_, cmd = m.Update(SyncedMsg{Err: fmt.Errorf("wrapped: %w", sync.ErrLocked), Reloaded: true, Articles: articles})
+	assert.Equal(t, StatusMsg("Another sync is running; skipped"), cmd())
+}
+
+func TestUpdate_WeekView(t *testing.T) {
+	articles := []readings.Article{
+		{ID: "1", Title: "First", URL: "https://go.dev/a"},
+		{ID: "2", Title: "Second"},
+		{ID: "3", Title: "Third"},
+	}
+	m := Model{articles: articles, filteredArticles: articles, view: ViewList, weeklyGoal: 3, width: 80, height: 20}
+	m.setWeek([]string{"2", "1", "gone"})
+
+	list := m.View()
+	assert.Contains(t, list, "3/3 planned this week")
+	assert.Contains(t, list, "● First")
+	assert.NotContains(t, list, "● Third")
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	model := newM.(Model)
+	assert.Equal(t, ViewWeek, model.view)
+	week := model.View()
+	assert.Contains(t, week, "1. Second")
+	assert.Contains(t, week, "2. First")
+	assert.Contains(t, week, "3. Not in the cache")
+
+	// Moving reorders right away and follows the item.
+	newM, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	model = newM.(Model)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, []string{"1", "2", "gone"}, model.weekList)
+	assert.Equal(t, 1, model.weekCursor)
+
+	// The first item cannot move up.
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	model = newM.(Model)
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	model = newM.(Model)
+	assert.Nil(t, cmd)
+	assert.Equal(t, 0, model.weekCursor)
+
+	newM, cmd = model.Update(WeekMovedMsg{ReadingList: []string{"1", "2", "gone"}, Queued: 1})
+	model = newM.(Model)
+	assert.Equal(t, StatusMsg("Moved (1 change not yet in Notion)"), cmd())
+
+	// Moves made while one is saved wait for it, then the final order is
+	// saved instead of the stale one being shown.
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	model = newM.(Model)
+	assert.NotNil(t, cmd)
+	newM, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("J")})
+	model = newM.(Model)
+	assert.Nil(t, cmd)
+	assert.Equal(t, []string{"2", "gone", "1"}, model.weekList)
+	newM, cmd = model.Update(WeekMovedMsg{ReadingList: []string{"2", "1", "gone"}, Queued: 1})
+	model = newM.(Model)
+	assert.NotNil(t, cmd)
+	assert.Equal(t, []string{"2", "gone", "1"}, model.weekList)
+	assert.True(t, model.moving)
+	newM, cmd = model.Update(WeekMovedMsg{ReadingList: []string{"2", "gone", "1"}, Queued: 2})
+	model = newM.(Model)
+	assert.False(t, model.moving)
+	assert.Equal(t, StatusMsg("Moved (2 changes not yet in Notion)"), cmd())
+
+	// Removing the last item keeps the cursor on the list.
+	model.weekCursor = 2
+	newM, _ = model.Update(WeekToggledMsg{Added: false, ReadingList: []string{"1", "2"}})
+	model = newM.(Model)
+	assert.Equal(t, 1, model.weekCursor)
+
+	newM, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ViewList, newM.(Model).view)
+}
@@ -1,10 +1,13 @@
 package tui
 
 import (
-"fmt"
-"strings"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss"
+	"productivity.go/internal/readings"
 )
 
 func (m Model) View() string {
@@ -12,18 +15,20 @@ func (m Model) View() string {
 
 	var content string
 	switch m.view {
-	case ViewList:
+	case ViewList, ViewSearch:
 		content = m.viewList(styles)
 	case ViewDetail:
 		content = m.viewDetail(styles)
 	case ViewFilter:
 		content = m.viewFilter(styles)
+	case ViewWeek:
+		content = m.viewWeek(styles)
 	default:
 		content = "Unknown view"
 	}
 
 	help := m.helpView(styles)
-	
+
 	finalView := lipgloss.JoinVertical(lipgloss.Left, content, help)
 
 	if m.statusMessage != "" {
@@ -37,13 +42,23 @@ func (m Model) View() string {
 func (m Model) viewList(styles Styles) string {
 	var b strings.Builder
 
-	b.WriteString(styles.Title.Render("Readings"))
+	switch {
+	case m.view == ViewSearch:
+		b.WriteString(m.searchInput.View())
+	case m.searchQuery != "":
+		b.WriteString(styles.Title.Render(fmt.Sprintf("Readings · %q", m.searchQuery)))
+	default:
+		b.WriteString(styles.Title.Render("Readings"))
+	}
+	if progress := m.weekProgress(); progress != "" {
+		b.WriteString(" " + styles.HelpDesc.Render(progress))
+	}
 	b.WriteString("\n\n")
 
 	if len(m.filteredArticles) == 0 {
 		b.WriteString(styles.Item.Render("No articles found."))
 		// Fill remaining height
-		linesUsed := 3 // Title + 2 newlines + 1 item
+		linesUsed := 3                              // Title + 2 newlines + 1 item
 		for i := 0; i < m.height-linesUsed-1; i++ { // -1 for help bar
 			b.WriteString("\n")
 		}
@@ -73,9 +88,20 @@ func (m Model) viewList(styles Styles) string {
 			title = "Untitled"
 		}
 
-		if i == m.cursor {
+		positions := m.searchMatches[article.ID]
+		if m.weekIDs[article.ID] {
+			title = plannedMarker + title
+			positions = shiftPositions(positions, utf8.RuneCountInString(plannedMarker))
+		}
+		switch {
+		case len(positions) > 0 && i == m.cursor:
+			base := styles.SelectedItem.UnsetPaddingLeft()
+			b.WriteString(styles.Item.Render(base.Render("> ") + highlight(title, positions, base, styles.Match)))
+		case len(positions) > 0:
+			b.WriteString(styles.Item.Render(highlight(title, positions, lipgloss.NewStyle(), styles.Match)))
+		case i == m.cursor:
 			b.WriteString(styles.SelectedItem.Render("> " + title))
-		} else {
+		default:
 			b.WriteString(styles.Item.Render(title))
 		}
 		b.WriteString("\n")
@@ -91,27 +117,265 @@ func (m Model) viewList(styles Styles) string {
 	return b.String()
 }
 
+// plannedMarker precedes articles on the planned week's reading list.
+const plannedMarker = "● "
+
+func shiftPositions(positions []int, n int) []int {
+	if len(positions) == 0 {
+		return positions
+	}
+	shifted := make([]int, len(positions))
+	for i, p := range positions {
+		shifted[i] = p + n
+	}
+	return shifted
+}
+
+// weekProgress counts the articles planned for the week against the weekly
+// goal, or returns "" until the week is loaded.
+func (m Model) weekProgress() string {
+	if m.weekIDs == nil {
+		return ""
+	}
+	if m.weeklyGoal > 0 {
+		return fmt.Sprintf("%d/%d planned %s", len(m.weekList), m.weeklyGoal, m.weekName())
+	}
+	return fmt.Sprintf("%d planned %s", len(m.weekList), m.weekName())
+}
+
+// weekName names the planned week, such as "next week".
+func (m Model) weekName() string {
+	if name := m.relativeWeek(); name != "" {
+		return name
+	}
+	start := m.weekStart
+	if start.IsZero() {
+		start = m.weekTime()
+	}
+	return "the week of " + start.Format("Jan 2")
+}
+
+// relativeWeek returns "this week", "next week" or "last week" for the
+// planned week, or "" for weeks further away.
+func (m Model) relativeWeek() string {
+	if m.weekAt.IsZero() {
+		return "this week"
+	}
+	now := time.Now()
+	switch {
+	case m.weekStart.IsZero():
+		return ""
+	case !now.Before(m.weekStart) && !now.After(m.weekEnd):
+		return "this week"
+	case m.weekStart.After(now) && !m.weekStart.After(now.AddDate(0, 0, 7)):
+		return "next week"
+	case m.weekEnd.Before(now) && !m.weekEnd.Before(now.AddDate(0, 0, -7)):
+		return "last week"
+	}
+	return ""
+}
+
+func (m Model) viewWeek(styles Styles) string {
+	var b strings.Builder
+
+	title := capitalize(m.relativeWeek())
+	switch {
+	case title == "" && m.weekStart.IsZero():
+		title = capitalize(m.weekName())
+	case title == "":
+		title = "Week"
+	}
+	if !m.weekStart.IsZero() {
+		title += fmt.Sprintf(" · %s – %s", m.weekStart.Format("Jan 2"), m.weekEnd.Format("Jan 2"))
+	}
+	b.WriteString(styles.Title.Render(title))
+	if progress := m.weekProgress(); progress != "" {
+		b.WriteString(" " + styles.HelpDesc.Render(progress))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.weekErr != nil:
+		b.WriteString(styles.Item.Render(fmt.Sprintf("No week to plan: %v", m.weekErr)))
+	case m.weekIDs == nil:
+		b.WriteString(styles.Item.Render("Loading…"))
+	case len(m.weekList) == 0:
+		b.WriteString(styles.Item.Render("Nothing planned yet. Press enter on an article in the list to add it."))
+	}
+
+	for i := range m.weekList {
+		label := "Not in the cache (done, or not synced yet)"
+		if a, ok := m.weekArticle(i); ok {
+			label = displayTitle(a)
+			if host := urlHost(a.URL); host != "" {
+				label += styles.DetailInfo.Render(" · " + host)
+			}
+		}
+		line := fmt.Sprintf("%d. %s", i+1, label)
+		if i == m.weekCursor {
+			b.WriteString(styles.SelectedItem.Render("> " + line))
+		} else {
+			b.WriteString(styles.Item.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	if m.proposal != nil {
+		b.WriteString("\n")
+		b.WriteString(styles.Title.Render(fmt.Sprintf("Proposed: %d more, ~%d min in total", len(m.proposal.Proposed), m.proposal.Minutes)))
+		b.WriteString("\n\n")
+		for i, a := range m.proposal.Proposed {
+			label := fmt.Sprintf("+ %d. %s", len(m.weekList)+i+1, displayTitle(a))
+			if len(a.Tags) > 0 {
+				label += styles.DetailInfo.Render(" · " + strings.Join(a.Tags, ", "))
+			}
+			b.WriteString(styles.Item.Render(label))
+			b.WriteString("\n")
+		}
+	}
+
+	return lipgloss.Place(m.width, m.height-1, lipgloss.Top, lipgloss.Left, b.String())
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func displayTitle(a readings.Article) string {
+	if a.Title == "" {
+		return "Untitled"
+	}
+	return a.Title
+}
+
+// highlight renders text with base, emphasizing the runes at positions with
+// match layered on top of base.
+func highlight(text string, positions []int, base, match lipgloss.Style) string {
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+	emphasis := match.Inherit(base)
+
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(emphasis.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+
+	for i, r := range []rune(text) {
+		if matched[i] != runMatched {
+			flush()
+			runMatched = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+
+	return b.String()
+}
+
 func (m Model) viewDetail(styles Styles) string {
 	if m.cursor >= len(m.filteredArticles) {
 		return "No article selected"
 	}
 	article := m.filteredArticles[m.cursor]
 
+	title := article.Title
+	if title == "" {
+		title = "Untitled"
+	}
+
 	var b strings.Builder
-	b.WriteString(styles.DetailTitle.Render(article.Title))
+	b.WriteString(styles.DetailTitle.Render(title))
 	b.WriteString("\n\n")
-	b.WriteString(styles.DetailInfo.Render(fmt.Sprintf("URL: %s", article.URL)))
-	b.WriteString("\n")
-	b.WriteString(styles.DetailInfo.Render(fmt.Sprintf("Fetched: %s", article.FetchedAt.Format("2006-01-02 15:04"))))
-	
+
+	fields := []struct{ label, value string }{
+		{"URL", article.URL},
+		{"Site", orUnknown(urlHost(article.URL))},
+		{"Tags", orUnknown(strings.Join(article.Tags, ", "))},
+		{capitalize(m.weekName()), m.weekStatus(article.ID)},
+		{"Added", formatDate(article.CreatedAt)},
+		{"Reading time", readingTime(article.ReadingMinutes)},
+		{"Fetched", article.FetchedAt.Format("2006-01-02 15:04")},
+	}
+	for _, f := range fields {
+		b.WriteString(styles.DetailLabel.Render(f.label + ":"))
+		b.WriteString(styles.DetailInfo.Render(f.value))
+		b.WriteString("\n")
+	}
+
+	if article.Notes != "" {
+		b.WriteString("\n")
+		width := m.width - 2
+		if width < 20 {
+			width = 20
+		}
+		b.WriteString(styles.DetailNotes.Width(width).Render(article.Notes))
+	}
+
 	content := b.String()
 	return lipgloss.Place(m.width, m.height-1, lipgloss.Top, lipgloss.Left, content)
 }
 
+// weekStatus describes whether the article is in the planned week's reading
+// list, which is loaded when the detail view first opens.
+func (m Model) weekStatus(id string) string {
+	switch {
+	case m.weekErr != nil && m.weekAt.IsZero():
+		return "unknown (no current week)"
+	case m.weekErr != nil:
+		return "unknown (no such week)"
+	case m.weekIDs == nil:
+		return "loading…"
+	case m.weekIDs[id]:
+		return "yes, on the reading list"
+	default:
+		return "no"
+	}
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+func readingTime(minutes int) string {
+	if minutes <= 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("~%d min", minutes)
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
 func (m Model) viewFilter(styles Styles) string {
 	var b strings.Builder
 
-	b.WriteString(styles.FilterTitle.Render("Filter by Tags"))
+	mode := "any"
+	if m.matchAllTags {
+		mode = "all"
+	}
+	b.WriteString(styles.FilterTitle.Render(fmt.Sprintf("Filter by Tags (match %s)", mode)))
 	b.WriteString("\n\n")
 
 	if len(m.tags) == 0 {
@@ -136,10 +400,11 @@ func (m Model) viewFilter(styles Styles) string {
 
 	for i := start; i < end; i++ {
 		tag := m.tags[i]
-		selected := m.selectedTags[tag]
 		prefix := "[ ] "
-		if selected {
+		if m.selectedTags[tag] {
 			prefix = "[x] "
+		} else if m.excludedTags[tag] {
+			prefix = "[-] "
 		}
 
 		if i == m.cursor {
@@ -149,7 +414,7 @@ func (m Model) viewFilter(styles Styles) string {
 		}
 		b.WriteString("\n")
 	}
-	
+
 	// Fill empty space
 	linesRendered := end - start
 	linesToFill := availableHeight - linesRendered
@@ -164,11 +429,24 @@ func (m Model) helpView(styles Styles) string {
 	var keys []string
 	switch m.view {
 	case ViewList:
-		keys = []string{"j/k", "nav", "/", "filter", "enter", "details", "q", "quit"}
+		keys = []string{"j/k", "nav", "/", "filter", "?", "search", "i", "details", "enter", "plan", "w", "week", "d", "done", "q", "quit"}
+		if m.searchQuery != "" {
+			keys = append(keys, "esc", "clear search")
+		}
 	case ViewDetail:
-		keys = []string{"enter", "open url", "esc", "back", "q", "back"}
+		keys = []string{"enter", "open url", "y", "copy url", "w", "week", "d", "done", "esc", "back"}
+	case ViewWeek:
+		keys = []string{"j/k", "nav", "J/K", "move", "x", "remove", "enter", "open url", "g", "plan", "[/]", "prev/next week", "esc", "back"}
+		if !m.weekAt.IsZero() {
+			keys = append(keys, "t", "this week")
+		}
+		if m.proposal != nil {
+			keys = []string{"enter/y", "add these", "g", "propose others", "esc/n", "discard"}
+		}
+	case ViewSearch:
+		keys = []string{"↑/↓", "nav", "enter", "keep results", "esc", "cancel"}
 	case ViewFilter:
-		keys = []string{"j/k", "nav", "space", "toggle", "right", "all", "enter", "apply", "esc", "cancel"}
+		keys = []string{"j/k", "nav", "space", "toggle", "x", "exclude", "a", "any/all", "right", "all", "enter", "apply", "esc", "cancel"}
 	}
 
 	var b strings.Builder
@@ -177,11 +455,15 @@ func (m Model) helpView(styles Styles) string {
 		b.WriteString(" ")
 		b.WriteString(styles.HelpDesc.Render(keys[i+1]))
 	}
-	
+
 	// Append input buffer if present
 	if m.inputBuffer != "" {
 		b.WriteString(styles.HelpKey.Render(fmt.Sprintf("  %s", m.inputBuffer)))
 	}
 
+	if m.syncing {
+		b.WriteString(styles.HelpDesc.Render("  " + m.spinner.View() + " syncing…"))
+	}
+
 	return styles.HelpBar.Width(m.width).Render(b.String())
 }